@@ -0,0 +1,738 @@
+package main
+
+import (
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+/**
+* Has all source generation methods from the serializer generator.
+* @author rnojiri
+**/
+
+const (
+	directive   string = "//serializer:generate"
+	jsonImport  string = "github.com/uol/serializer/json"
+	jsonAlias   string = "jsonserializer"
+	tagJSON     string = "json"
+	tagOpenTSDB string = "opentsdb"
+
+	roleMetric    string = "metric"
+	roleTimestamp string = "timestamp"
+	roleValue     string = "value"
+	roleTags      string = "tags"
+)
+
+type basicKind uint8
+
+const (
+	kindString basicKind = iota
+	kindInt
+	kindUint
+	kindFloat32
+	kindFloat64
+	kindBool
+)
+
+var basicKinds = map[string]basicKind{
+	"string":  kindString,
+	"int":     kindInt,
+	"int8":    kindInt,
+	"int16":   kindInt,
+	"int32":   kindInt,
+	"int64":   kindInt,
+	"rune":    kindInt,
+	"uint":    kindUint,
+	"uint8":   kindUint,
+	"uint16":  kindUint,
+	"uint32":  kindUint,
+	"uint64":  kindUint,
+	"uintptr": kindUint,
+	"byte":    kindUint,
+	"float32": kindFloat32,
+	"float64": kindFloat64,
+	"bool":    kindBool,
+}
+
+// conversions - the type used to convert a basic kind before appending it
+var conversions = map[basicKind]string{
+	kindString:  "string",
+	kindInt:     "int64",
+	kindUint:    "uint64",
+	kindFloat32: "float64",
+	kindFloat64: "float64",
+	kindBool:    "bool",
+}
+
+// floatBits - the bit size of the float kinds
+var floatBits = map[basicKind]int{
+	kindFloat32: 32,
+	kindFloat64: 64,
+}
+
+// generator - keeps the parsed package and the generated code
+type generator struct {
+	typeSpecs   map[string]ast.Expr
+	code        strings.Builder
+	literal     strings.Builder
+	usesJSON    bool
+	usesStrconv bool
+	usesSort    bool
+}
+
+// openTSDBField - a struct field used to build an OpenTSDB line
+type openTSDBField struct {
+	role string
+	expr string
+	typ  ast.Expr
+}
+
+// generate - parses the package in the directory and returns the formatted generated source
+func generate(dir, outputName string, typeNames []string) ([]byte, error) {
+
+	files, err := filepath.Glob(filepath.Join(dir, "*.go"))
+	if err != nil {
+		return nil, err
+	}
+
+	g := &generator{
+		typeSpecs: map[string]ast.Expr{},
+	}
+
+	var pkgName string
+	var directives []string
+	fset := token.NewFileSet()
+
+	for _, file := range files {
+
+		base := filepath.Base(file)
+		if strings.HasSuffix(base, "_test.go") || base == outputName {
+			continue
+		}
+
+		f, err := parser.ParseFile(fset, file, nil, parser.ParseComments)
+		if err != nil {
+			return nil, err
+		}
+
+		pkgName = f.Name.Name
+
+		for _, decl := range f.Decls {
+
+			genDecl, ok := decl.(*ast.GenDecl)
+			if !ok || genDecl.Tok != token.TYPE {
+				continue
+			}
+
+			for _, spec := range genDecl.Specs {
+
+				typeSpec := spec.(*ast.TypeSpec)
+				g.typeSpecs[typeSpec.Name.Name] = typeSpec.Type
+
+				if hasDirective(typeSpec.Doc) || (len(genDecl.Specs) == 1 && hasDirective(genDecl.Doc)) {
+					directives = append(directives, typeSpec.Name.Name)
+				}
+			}
+		}
+	}
+
+	if pkgName == "" {
+		return nil, fmt.Errorf("no go source files found in: %s", dir)
+	}
+
+	if len(typeNames) == 0 {
+		typeNames = directives
+	}
+
+	if len(typeNames) == 0 {
+		return nil, fmt.Errorf("no types to generate, use the -type flag or the %s directive", directive)
+	}
+
+	for _, name := range typeNames {
+
+		st, ok := g.structType(&ast.Ident{Name: name})
+		if !ok {
+			return nil, fmt.Errorf("type is not a struct declared in the package: %s", name)
+		}
+
+		err = g.generateJSON(name, st)
+		if err != nil {
+			return nil, err
+		}
+
+		if g.hasOpenTSDBTags(st) {
+			err = g.generateOpenTSDB(name, st)
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	var source strings.Builder
+	source.WriteString("// Code generated by serializergen. DO NOT EDIT.\n\n")
+	source.WriteString("package " + pkgName + "\n\n")
+	source.WriteString("import (\n")
+	if g.usesSort {
+		source.WriteString("\"sort\"\n")
+	}
+	if g.usesStrconv {
+		source.WriteString("\"strconv\"\n")
+	}
+	if g.usesJSON {
+		source.WriteString("\n" + jsonAlias + " " + strconv.Quote(jsonImport) + "\n")
+	}
+	source.WriteString(")\n")
+	source.WriteString(g.code.String())
+
+	return format.Source([]byte(source.String()))
+}
+
+// hasDirective - checks if the comment group has the generation directive
+func hasDirective(doc *ast.CommentGroup) bool {
+
+	if doc == nil {
+		return false
+	}
+
+	for _, comment := range doc.List {
+		if strings.TrimSpace(comment.Text) == directive {
+			return true
+		}
+	}
+
+	return false
+}
+
+// structType - resolves the expression to a struct declared in the package
+func (g *generator) structType(expr ast.Expr) (*ast.StructType, bool) {
+
+	switch t := expr.(type) {
+	case *ast.StructType:
+		return t, true
+	case *ast.Ident:
+		spec, ok := g.typeSpecs[t.Name]
+		if !ok {
+			return nil, false
+		}
+		return g.structType(spec)
+	default:
+		return nil, false
+	}
+}
+
+// basicType - resolves the expression to a basic kind, returns the expression type name too
+func (g *generator) basicType(expr ast.Expr) (basicKind, string, bool) {
+
+	ident, ok := expr.(*ast.Ident)
+	if !ok {
+		return 0, "", false
+	}
+
+	if kind, ok := basicKinds[ident.Name]; ok {
+		return kind, ident.Name, true
+	}
+
+	spec, ok := g.typeSpecs[ident.Name]
+	if !ok {
+		return 0, "", false
+	}
+
+	kind, _, ok := g.basicType(spec)
+
+	return kind, ident.Name, ok
+}
+
+// convert - converts the expression to the type used to append a basic kind
+func convert(kind basicKind, typeName, expr string) string {
+
+	conversion := conversions[kind]
+	if conversion == typeName {
+		return expr
+	}
+
+	return conversion + "(" + expr + ")"
+}
+
+// fieldNames - returns the field names, embedded fields are named by their type
+func fieldNames(field *ast.Field) ([]string, error) {
+
+	if len(field.Names) > 0 {
+		names := make([]string, len(field.Names))
+		for i, name := range field.Names {
+			names[i] = name.Name
+		}
+		return names, nil
+	}
+
+	ident, ok := field.Type.(*ast.Ident)
+	if !ok {
+		return nil, fmt.Errorf("unsupported embedded field type: %T", field.Type)
+	}
+
+	return []string{ident.Name}, nil
+}
+
+// lookupTag - returns the tag name from the field
+func lookupTag(field *ast.Field, key string) (string, bool) {
+
+	if field.Tag == nil {
+		return "", false
+	}
+
+	raw, err := strconv.Unquote(field.Tag.Value)
+	if err != nil {
+		return "", false
+	}
+
+	tag, ok := reflect.StructTag(raw).Lookup(key)
+	if !ok {
+		return "", false
+	}
+
+	return strings.Split(tag, ",")[0], true
+}
+
+// writeLiteral - buffers a constant part of the output
+func (g *generator) writeLiteral(value string) {
+
+	g.literal.WriteString(value)
+}
+
+// writeStatement - writes a statement, flushing the buffered constant parts before it
+func (g *generator) writeStatement(format string, args ...interface{}) {
+
+	g.flushLiteral()
+	g.code.WriteString(fmt.Sprintf(format, args...))
+	g.code.WriteByte('\n')
+}
+
+// flushLiteral - writes the buffered constant parts as a single append
+func (g *generator) flushLiteral() {
+
+	if g.literal.Len() == 0 {
+		return
+	}
+
+	value := g.literal.String()
+	g.literal.Reset()
+
+	if len(value) == 1 {
+		g.code.WriteString("dst = append(dst, " + strconv.QuoteRune(rune(value[0])) + ")\n")
+	} else {
+		g.code.WriteString("dst = append(dst, " + strconv.Quote(value) + "...)\n")
+	}
+}
+
+// escapeJSON - escapes the string the same way the template serializer does
+func escapeJSON(value string) string {
+
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(value) + `"`
+}
+
+// generateJSON - generates the AppendJSON method
+func (g *generator) generateJSON(name string, st *ast.StructType) error {
+
+	g.code.WriteString("\n// AppendJSON - appends the JSON representation of " + name + " to dst\n")
+	g.code.WriteString("func (x *" + name + ") AppendJSON(dst []byte) []byte {\n")
+
+	g.writeLiteral("{")
+
+	_, err := g.writeJSONStruct(st, "x", false)
+	if err != nil {
+		return fmt.Errorf("%s: %s", name, err)
+	}
+
+	g.writeLiteral("}")
+	g.flushLiteral()
+
+	g.code.WriteString("return dst\n}\n")
+
+	return nil
+}
+
+// writeJSONStruct - writes all struct properties (mirrors the template struct mapping), returns if any property was written
+func (g *generator) writeJSONStruct(st *ast.StructType, expr string, separator bool) (bool, error) {
+
+	written := false
+
+	for _, field := range st.Fields.List {
+
+		names, err := fieldNames(field)
+		if err != nil {
+			return false, err
+		}
+
+		tagName, tagged := lookupTag(field, tagJSON)
+
+		for _, name := range names {
+
+			fieldExpr := expr + "." + name
+			needsSeparator := separator || written
+
+			if sub, ok := g.structType(field.Type); ok {
+
+				if tagged {
+					g.writeJSONProperty(tagName, needsSeparator)
+					g.writeLiteral("{")
+				}
+
+				wroteProperties, err := g.writeJSONStruct(sub, fieldExpr, needsSeparator && !tagged)
+				if err != nil {
+					return false, err
+				}
+
+				if tagged {
+					g.writeLiteral("}")
+				}
+
+				written = written || tagged || wroteProperties
+
+				continue
+			}
+
+			if !tagged {
+				continue
+			}
+
+			g.writeJSONProperty(tagName, needsSeparator)
+			written = true
+
+			err = g.writeJSONValue(field.Type, fieldExpr)
+			if err != nil {
+				return false, fmt.Errorf("field %s: %s", name, err)
+			}
+		}
+	}
+
+	return written, nil
+}
+
+// writeJSONProperty - writes a property name
+func (g *generator) writeJSONProperty(name string, separator bool) {
+
+	if separator {
+		g.writeLiteral(",")
+	}
+
+	g.writeLiteral(escapeJSON(name) + ":")
+}
+
+// writeJSONValue - writes a variable value
+func (g *generator) writeJSONValue(typ ast.Expr, expr string) error {
+
+	switch t := typ.(type) {
+	case *ast.MapType:
+
+		keyKind, keyType, ok := g.basicType(t.Key)
+		if !ok || keyKind != kindString {
+			return fmt.Errorf("only string map keys are supported")
+		}
+
+		// like the type keyed template serializer, the nil maps are written as null
+		g.writeStatement("if %s == nil {\ndst = append(dst, \"null\"...)\n} else {", expr)
+		g.writeLiteral("{")
+		g.usesSort = true
+		g.writeStatement("keys := make([]string, 0, len(%s))", expr)
+		g.writeStatement("for k := range %s {\nkeys = append(keys, %s)\n}", expr, convert(kindString, keyType, "k"))
+		g.writeStatement("sort.Strings(keys)")
+		g.writeStatement("for i, k := range keys {")
+		g.writeStatement("if i > 0 {\ndst = append(dst, ',')\n}")
+		g.usesJSON = true
+		g.writeStatement("dst = %s.AppendString(dst, k)", jsonAlias)
+		g.writeStatement("dst = append(dst, ':')")
+
+		key := "k"
+		if keyType != "string" {
+			key = keyType + "(k)"
+		}
+
+		err := g.writeJSONElement(t.Value, expr+"["+key+"]")
+		if err != nil {
+			return err
+		}
+
+		g.writeStatement("}")
+		g.writeLiteral("}")
+		g.writeStatement("}")
+
+		return nil
+
+	case *ast.ArrayType:
+
+		slice := t.Len == nil
+		if slice {
+			g.writeStatement("if %s == nil {\ndst = append(dst, \"null\"...)\n} else {", expr)
+		}
+
+		g.writeLiteral("[")
+		g.writeStatement("for i := 0; i < len(%s); i++ {", expr)
+		g.writeStatement("if i > 0 {\ndst = append(dst, ',')\n}")
+
+		err := g.writeJSONElement(t.Elt, expr+"[i]")
+		if err != nil {
+			return err
+		}
+
+		g.writeStatement("}")
+		g.writeLiteral("]")
+
+		if slice {
+			g.writeStatement("}")
+		}
+
+		return nil
+	}
+
+	kind, typeName, ok := g.basicType(typ)
+	if !ok {
+		return fmt.Errorf("unsupported type")
+	}
+
+	g.writeBasic(kind, typeName, expr, true)
+
+	return nil
+}
+
+// writeJSONElement - writes a map or array element
+func (g *generator) writeJSONElement(typ ast.Expr, expr string) error {
+
+	kind, typeName, ok := g.basicType(typ)
+	if !ok {
+		return fmt.Errorf("only basic types are supported as map values and array elements")
+	}
+
+	g.writeBasic(kind, typeName, expr, true)
+
+	return nil
+}
+
+// writeBasic - writes a basic kind value with the default format of the JSON (when in JSON format, quoting the strings)
+// or the OpenTSDB template serializer
+func (g *generator) writeBasic(kind basicKind, typeName, expr string, jsonFormat bool) {
+
+	converted := convert(kind, typeName, expr)
+
+	switch kind {
+	case kindString:
+		if jsonFormat {
+			g.usesJSON = true
+			g.writeStatement("dst = %s.AppendString(dst, %s)", jsonAlias, converted)
+		} else {
+			g.writeStatement("dst = append(dst, %s...)", converted)
+		}
+	case kindInt:
+		g.usesStrconv = true
+		g.writeStatement("dst = strconv.AppendInt(dst, %s, 10)", converted)
+	case kindUint:
+		g.usesStrconv = true
+		g.writeStatement("dst = strconv.AppendUint(dst, %s, 10)", converted)
+	case kindFloat32, kindFloat64:
+		if jsonFormat {
+			g.usesJSON = true
+			g.writeStatement("dst = %s.AppendFloat(dst, %s, %d)", jsonAlias, converted, floatBits[kind])
+		} else {
+			g.writeOpenTSDBFloat(converted)
+		}
+	case kindBool:
+		g.usesStrconv = true
+		g.writeStatement("dst = strconv.AppendBool(dst, %s)", converted)
+	}
+}
+
+// writeOpenTSDBFloat - writes the OpenTSDB value or float tag with the default format of the template serializer
+func (g *generator) writeOpenTSDBFloat(expr string) {
+
+	g.usesStrconv = true
+	g.writeStatement("dst = strconv.AppendFloat(dst, %s, 'f', -1, 64)", expr)
+}
+
+// hasOpenTSDBTags - checks if any field (including the embedded ones) has the opentsdb tag
+func (g *generator) hasOpenTSDBTags(st *ast.StructType) bool {
+
+	fields, err := g.openTSDBFields(st, "x")
+
+	return err != nil || len(fields) > 0
+}
+
+// openTSDBFields - lists the fields with the opentsdb tag, embedded structs are flattened
+func (g *generator) openTSDBFields(st *ast.StructType, expr string) ([]openTSDBField, error) {
+
+	fields := []openTSDBField{}
+
+	for _, field := range st.Fields.List {
+
+		names, err := fieldNames(field)
+		if err != nil {
+			return nil, err
+		}
+
+		role, tagged := lookupTag(field, tagOpenTSDB)
+
+		for _, name := range names {
+
+			fieldExpr := expr + "." + name
+
+			if !tagged {
+				if sub, ok := g.structType(field.Type); ok && len(field.Names) == 0 {
+					subFields, err := g.openTSDBFields(sub, fieldExpr)
+					if err != nil {
+						return nil, err
+					}
+					fields = append(fields, subFields...)
+				}
+				continue
+			}
+
+			fields = append(fields, openTSDBField{
+				role: role,
+				expr: fieldExpr,
+				typ:  field.Type,
+			})
+		}
+	}
+
+	return fields, nil
+}
+
+// generateOpenTSDB - generates the AppendOpenTSDB method
+func (g *generator) generateOpenTSDB(name string, st *ast.StructType) error {
+
+	fields, err := g.openTSDBFields(st, "x")
+	if err != nil {
+		return fmt.Errorf("%s: %s", name, err)
+	}
+
+	roles := map[string]*openTSDBField{}
+	tags := []openTSDBField{}
+
+	for i := range fields {
+		switch fields[i].role {
+		case roleMetric, roleTimestamp, roleValue:
+			if _, ok := roles[fields[i].role]; ok {
+				return fmt.Errorf("%s: duplicated opentsdb field: %s", name, fields[i].role)
+			}
+			roles[fields[i].role] = &fields[i]
+		default:
+			tags = append(tags, fields[i])
+		}
+	}
+
+	for _, role := range []string{roleMetric, roleTimestamp, roleValue} {
+		if _, ok := roles[role]; !ok {
+			return fmt.Errorf("%s: missing opentsdb field: %s", name, role)
+		}
+	}
+
+	g.code.WriteString("\n// AppendOpenTSDB - appends the OpenTSDB line of " + name + " to dst\n")
+	g.code.WriteString("func (x *" + name + ") AppendOpenTSDB(dst []byte) []byte {\n")
+
+	g.writeLiteral("put ")
+
+	kind, typeName, ok := g.basicType(roles[roleMetric].typ)
+	if !ok || kind != kindString {
+		return fmt.Errorf("%s: the metric must be a string", name)
+	}
+	g.writeBasic(kind, typeName, roles[roleMetric].expr, false)
+	g.writeLiteral(" ")
+
+	kind, typeName, ok = g.basicType(roles[roleTimestamp].typ)
+	if !ok || (kind != kindInt && kind != kindUint) {
+		return fmt.Errorf("%s: the timestamp must be an integer", name)
+	}
+	g.usesStrconv = true
+	g.writeStatement("dst = strconv.AppendInt(dst, int64(%s), 10)", roles[roleTimestamp].expr)
+	g.writeLiteral(" ")
+
+	kind, _, ok = g.basicType(roles[roleValue].typ)
+	if !ok || kind == kindString || kind == kindBool {
+		return fmt.Errorf("%s: the value must be a number", name)
+	}
+	g.writeOpenTSDBFloat("float64(" + roles[roleValue].expr + ")")
+	g.writeLiteral(" ")
+
+	err = g.writeOpenTSDBTags(tags)
+	if err != nil {
+		return fmt.Errorf("%s: %s", name, err)
+	}
+
+	g.writeLiteral("\n")
+	g.flushLiteral()
+
+	g.code.WriteString("return dst\n}\n")
+
+	return nil
+}
+
+// writeOpenTSDBTags - writes the tags separated by spaces, the map tags are sorted by key
+func (g *generator) writeOpenTSDBTags(tags []openTSDBField) error {
+
+	hasTags := false
+	dynamic := false
+
+	for _, tag := range tags {
+
+		if mapType, ok := tag.typ.(*ast.MapType); ok && tag.role == roleTags {
+
+			keyKind, keyType, ok := g.basicType(mapType.Key)
+			if !ok || keyKind != kindString {
+				return fmt.Errorf("only string map keys are supported")
+			}
+
+			valueKind, valueType, ok := g.basicType(mapType.Value)
+			if !ok {
+				return fmt.Errorf("only basic types are supported as tag values")
+			}
+
+			if !dynamic {
+				g.writeStatement("hasTags := %t", hasTags)
+				dynamic = true
+			}
+
+			g.usesSort = true
+			g.writeStatement("{")
+			g.writeStatement("keys := make([]string, 0, len(%s))", tag.expr)
+			g.writeStatement("for k := range %s {\nkeys = append(keys, %s)\n}", tag.expr, convert(kindString, keyType, "k"))
+			g.writeStatement("sort.Strings(keys)")
+			g.writeStatement("for _, k := range keys {")
+			g.writeStatement("if hasTags {\ndst = append(dst, ' ')\n}")
+			g.writeStatement("hasTags = true")
+			g.writeStatement("dst = append(dst, k...)")
+			g.writeStatement("dst = append(dst, '=')")
+
+			key := "k"
+			if keyType != "string" {
+				key = keyType + "(k)"
+			}
+			g.writeBasic(valueKind, valueType, tag.expr+"["+key+"]", false)
+			g.writeStatement("}\n}")
+
+			continue
+		}
+
+		kind, typeName, ok := g.basicType(tag.typ)
+		if !ok {
+			return fmt.Errorf("unsupported tag type: %s", tag.role)
+		}
+
+		if dynamic {
+			g.writeStatement("if hasTags {\ndst = append(dst, ' ')\n}")
+			g.writeStatement("hasTags = true")
+		} else if hasTags {
+			g.writeLiteral(" ")
+		}
+
+		hasTags = true
+
+		g.writeLiteral(tag.role + "=")
+		g.writeBasic(kind, typeName, tag.expr, false)
+	}
+
+	return nil
+}
@@ -0,0 +1,58 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+/**
+* Generates static (reflection free) serializers for the given struct types.
+*
+* Usage:
+*   serializergen [-type T1,T2] [-output file] [package directory]
+*
+* Without the -type flag, all types with a "//serializer:generate" comment are generated.
+* The output is the same of the template serializers with the default options, except the JSON map keys which are
+* always written sorted (like the WithSortedKeys option).
+* @author rnojiri
+**/
+
+const defaultOutput string = "serializer_generated.go"
+
+func main() {
+
+	typeNames := flag.String("type", "", "comma separated list of type names (uses the //serializer:generate directives if empty)")
+	output := flag.String("output", "", "output file name (default <package directory>/"+defaultOutput+")")
+	flag.Parse()
+
+	dir := "."
+	if flag.NArg() > 0 {
+		dir = flag.Arg(0)
+	}
+
+	outputFile := *output
+	if outputFile == "" {
+		outputFile = filepath.Join(dir, defaultOutput)
+	}
+
+	var types []string
+	if *typeNames != "" {
+		types = strings.Split(*typeNames, ",")
+	}
+
+	source, err := generate(dir, filepath.Base(outputFile), types)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "[serializergen]", err)
+		os.Exit(1)
+	}
+
+	err = ioutil.WriteFile(outputFile, source, 0644)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "[serializergen]", err)
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,311 @@
+package json
+
+import (
+	"bytes"
+	"crypto/sha256"
+	stdjson "encoding/json"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf16"
+	"unicode/utf8"
+
+	"github.com/uol/serializer/serializer"
+)
+
+/**
+* Has the canonical output (RFC 8785 - JSON Canonicalization Scheme) from the JSON serializer.
+* @author rnojiri
+**/
+
+const (
+	// maxSafeInteger - the biggest integer represented exactly by a double (2^53)
+	maxSafeInteger int64 = 1 << 53
+
+	// maxPlainExponent - numbers with bigger decimal exponents are written in the exponential form
+	maxPlainExponent int = 21
+
+	// minPlainExponent - numbers with smaller decimal exponents are written in the exponential form
+	minPlainExponent int = -6
+
+	strCanonicalTrue  string = "true"
+	strCanonicalFalse string = "false"
+	strEscapedBack    string = "\\b"
+	strEscapedFeed    string = "\\f"
+)
+
+// Digest - serializes the mapped JSON and returns the SHA-256 digest of the output (byte stable with WithCanonical)
+func (s *Serializer) Digest(name string, parameters ...interface{}) ([sha256.Size]byte, error) {
+
+	result, err := s.Serialize(name, parameters...)
+	if err != nil {
+		return [sha256.Size]byte{}, err
+	}
+
+	return sha256.Sum256([]byte(result)), nil
+}
+
+// utf16Less - compares two property names by their UTF-16 code units, as required by RFC 8785
+func (s *Serializer) utf16Less(a, b string) bool {
+
+	if a == b {
+		return false
+	}
+
+	ua := utf16.Encode([]rune(a))
+	ub := utf16.Encode([]rune(b))
+
+	for i := 0; i < len(ua) && i < len(ub); i++ {
+		if ua[i] != ub[i] {
+			return ua[i] < ub[i]
+		}
+	}
+
+	return len(ua) < len(ub)
+}
+
+// sortFields - sorts the struct properties by name in canonical mode
+func (s *Serializer) sortFields(fields []structField) {
+
+	sort.SliceStable(fields, func(i, j int) bool {
+		return s.utf16Less(fields[i].name, fields[j].name)
+	})
+}
+
+// writeCanonicalString - writes a string with the minimal escaping of RFC 8785, only the double quote, the
+// escape bar and the control characters are escaped (invalid UTF-8 is replaced by U+FFFD)
+func (s *Serializer) writeCanonicalString(value string, b *strings.Builder) {
+
+	b.WriteByte(byteValueDoubleQuote)
+
+	start := 0
+
+	for i := 0; i < len(value); {
+
+		c := value[i]
+
+		if c < utf8.RuneSelf {
+
+			if c >= 0x20 && c != byteValueDoubleQuote && c != byteValueEscapeBar {
+				i++
+				continue
+			}
+
+			b.WriteString(value[start:i])
+
+			switch c {
+			case byteValueDoubleQuote:
+				b.WriteString(jsonEscapedDoubleQuote)
+			case byteValueEscapeBar:
+				b.WriteString(jsonEscapedEscapeBar)
+			case '\n':
+				b.WriteString(strEscapedNewLine)
+			case '\r':
+				b.WriteString(strEscapedReturn)
+			case '\t':
+				b.WriteString(strEscapedTab)
+			case '\b':
+				b.WriteString(strEscapedBack)
+			case '\f':
+				b.WriteString(strEscapedFeed)
+			default:
+				b.WriteString(strUnicodeEscape)
+				b.WriteByte(hexDigits[c>>4])
+				b.WriteByte(hexDigits[c&0xF])
+			}
+
+			i++
+			start = i
+
+			continue
+		}
+
+		r, size := utf8.DecodeRuneInString(value[i:])
+		if r == utf8.RuneError && size == 1 {
+			b.WriteString(value[start:i])
+			b.WriteRune(utf8.RuneError)
+			i += size
+			start = i
+			continue
+		}
+
+		i += size
+	}
+
+	b.WriteString(value[start:])
+	b.WriteByte(byteValueDoubleQuote)
+}
+
+// formatCanonicalNumber - formats a double like the ECMAScript Number.prototype.toString does, as required by RFC 8785
+func (s *Serializer) formatCanonicalNumber(f float64) (string, error) {
+
+	if math.IsInf(f, 0) || math.IsNaN(f) {
+		return serializer.Empty, fmt.Errorf("unsupported number in canonical json: %s", strconv.FormatFloat(f, 'g', -1, 64))
+	}
+
+	if f == 0 {
+		return strZeroNumber, nil
+	}
+
+	var b strings.Builder
+
+	if f < 0 {
+		b.WriteByte('-')
+		f = -f
+	}
+
+	// shortest digits in the form d.ddde±x
+	scientific := strconv.FormatFloat(f, 'e', -1, 64)
+	exponentAt := strings.IndexByte(scientific, byteFloatExponent)
+
+	digits := strings.Replace(scientific[:exponentAt], strDot, serializer.Empty, 1)
+
+	exponent, err := strconv.Atoi(scientific[exponentAt+1:])
+	if err != nil {
+		return serializer.Empty, err
+	}
+
+	k := len(digits)
+	n := exponent + 1
+
+	switch {
+	case k <= n && n <= maxPlainExponent:
+		b.WriteString(digits)
+		b.WriteString(strings.Repeat(strZeroNumber, n-k))
+	case 0 < n && n <= maxPlainExponent:
+		b.WriteString(digits[:n])
+		b.WriteString(strDot)
+		b.WriteString(digits[n:])
+	case minPlainExponent < n && n <= 0:
+		b.WriteString("0.")
+		b.WriteString(strings.Repeat(strZeroNumber, -n))
+		b.WriteString(digits)
+	default:
+		b.WriteByte(digits[0])
+		if k > 1 {
+			b.WriteString(strDot)
+			b.WriteString(digits[1:])
+		}
+		b.WriteByte(byteFloatExponent)
+		if n-1 >= 0 {
+			b.WriteByte('+')
+		}
+		b.WriteString(strconv.Itoa(n - 1))
+	}
+
+	return b.String(), nil
+}
+
+// formatCanonicalInteger - formats an integer, the ones not represented exactly by a double are rounded like
+// any other JSON number in RFC 8785
+func (s *Serializer) formatCanonicalInteger(i int64) (string, error) {
+
+	if i > -maxSafeInteger && i < maxSafeInteger {
+		return strconv.FormatInt(i, 10), nil
+	}
+
+	return s.formatCanonicalNumber(float64(i))
+}
+
+// formatCanonicalUnsigned - formats an unsigned integer like formatCanonicalInteger does
+func (s *Serializer) formatCanonicalUnsigned(u uint64) (string, error) {
+
+	if u < uint64(maxSafeInteger) {
+		return strconv.FormatUint(u, 10), nil
+	}
+
+	return s.formatCanonicalNumber(float64(u))
+}
+
+// canonicalize - rewrites a JSON fragment (raw values and custom encoders outputs) in the canonical form
+func (s *Serializer) canonicalize(fragment string) (string, error) {
+
+	decoder := stdjson.NewDecoder(bytes.NewReader([]byte(fragment)))
+	decoder.UseNumber()
+
+	var tree interface{}
+
+	err := decoder.Decode(&tree)
+	if err != nil {
+		return serializer.Empty, fmt.Errorf("invalid json fragment: %s", err.Error())
+	}
+
+	if decoder.More() {
+		return serializer.Empty, fmt.Errorf("invalid json fragment: %s", fragment)
+	}
+
+	var b strings.Builder
+
+	err = s.writeCanonicalTree(tree, &b)
+	if err != nil {
+		return serializer.Empty, err
+	}
+
+	return b.String(), nil
+}
+
+// writeCanonicalTree - writes a decoded JSON tree in the canonical form
+func (s *Serializer) writeCanonicalTree(tree interface{}, b *strings.Builder) error {
+
+	switch value := tree.(type) {
+	case nil:
+		b.WriteString(serializer.Null)
+	case bool:
+		if value {
+			b.WriteString(strCanonicalTrue)
+		} else {
+			b.WriteString(strCanonicalFalse)
+		}
+	case string:
+		s.writeCanonicalString(value, b)
+	case stdjson.Number:
+		f, err := value.Float64()
+		if err != nil {
+			return fmt.Errorf("invalid number in json fragment: %s", value.String())
+		}
+		formatted, err := s.formatCanonicalNumber(f)
+		if err != nil {
+			return err
+		}
+		b.WriteString(formatted)
+	case []interface{}:
+		b.WriteString(strSquareBracketLeft)
+		for i, item := range value {
+			if i > 0 {
+				b.WriteString(strComma)
+			}
+			err := s.writeCanonicalTree(item, b)
+			if err != nil {
+				return err
+			}
+		}
+		b.WriteString(strSquareBracketRight)
+	case map[string]interface{}:
+		keys := make([]string, 0, len(value))
+		for key := range value {
+			keys = append(keys, key)
+		}
+		sort.Slice(keys, func(i, j int) bool {
+			return s.utf16Less(keys[i], keys[j])
+		})
+		b.WriteString(strBracketLeft)
+		for i, key := range keys {
+			if i > 0 {
+				b.WriteString(strComma)
+			}
+			s.writeCanonicalString(key, b)
+			b.WriteString(strColon)
+			err := s.writeCanonicalTree(value[key], b)
+			if err != nil {
+				return err
+			}
+		}
+		b.WriteString(strBracketRight)
+	default:
+		return fmt.Errorf("unexpected json fragment type: %T", tree)
+	}
+
+	return nil
+}
@@ -0,0 +1,66 @@
+package json
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/uol/serializer/serializer"
+)
+
+/**
+* Has the size bounded serialization methods from the JSON serializer.
+* @author rnojiri
+**/
+
+// SerializeArrayChunked - serializes the items in a sequence of JSON arrays with at most maxBytes each
+func (s *Serializer) SerializeArrayChunked(maxBytes int, items ...*ArrayItem) (chunks []serializer.Chunk, err error) {
+
+	defer s.options.Recover(&err)
+
+	chunks = []serializer.Chunk{}
+
+	var b strings.Builder
+	var indexes []int
+
+	closeChunk := func() {
+		b.WriteString(strSquareBracketRight)
+		chunks = append(chunks, serializer.Chunk{
+			Payload: b.String(),
+			Indexes: indexes,
+		})
+		b = strings.Builder{}
+		indexes = nil
+	}
+
+	for i := 0; i < len(items); i++ {
+
+		result, err := s.serializeItem(items[i], false)
+		if err != nil {
+			return nil, err
+		}
+
+		if len(result)+2 > maxBytes {
+			return nil, fmt.Errorf("item on index %d exceeds the limit of %d bytes", i, maxBytes)
+		}
+
+		if len(indexes) > 0 && b.Len()+len(result)+2 > maxBytes {
+			closeChunk()
+		}
+
+		if len(indexes) == 0 {
+			b.Grow(s.bufferSize)
+			b.WriteString(strSquareBracketLeft)
+		} else {
+			b.WriteString(strComma)
+		}
+
+		b.WriteString(result)
+		indexes = append(indexes, i)
+	}
+
+	if len(indexes) > 0 {
+		closeChunk()
+	}
+
+	return chunks, nil
+}
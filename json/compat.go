@@ -0,0 +1,412 @@
+package json
+
+import (
+	"bytes"
+	"encoding"
+	stdjson "encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/uol/serializer/serializer"
+)
+
+/**
+* Has the encoding/json compatibility mode from the JSON serializer.
+* @author rnojiri
+**/
+
+const (
+	tagOmitEmpty          string = "omitempty"
+	tagOmitZero           string = "omitzero"
+	tagString             string = "string"
+	strEscapedNewLine     string = "\\n"
+	strEscapedReturn      string = "\\r"
+	strEscapedTab         string = "\\t"
+	strLineSeparator      string = "\\u2028"
+	strParagraphSeparator string = "\\u2029"
+	strZeroNumber         string = "0"
+	byteFloatExponent     byte   = 'e'
+	byteFloatDecimal      byte   = 'f'
+	minPlainFloat                = 1e-6
+	maxPlainFloat                = 1e21
+)
+
+var (
+	marshalerType = reflect.TypeOf((*stdjson.Marshaler)(nil)).Elem()
+	isZeroerType  = reflect.TypeOf((*interface{ IsZero() bool })(nil)).Elem()
+	numberType    = reflect.TypeOf(stdjson.Number(""))
+
+	// the escaping of these characters changed between the Go versions, the linked encoding/json defines them
+	compatBackspace   = stdEscape("\b")
+	compatFormFeed    = stdEscape("\f")
+	compatInvalidRune = stdEscape("\xff")
+)
+
+// stdEscape - returns how encoding/json escapes the string (without the quotes)
+func stdEscape(value string) string {
+
+	escaped, err := stdjson.Marshal(value)
+	if err != nil {
+		panic(err)
+	}
+
+	return string(escaped[1 : len(escaped)-1])
+}
+
+// tagOptions - returns the omitempty, omitzero, string (used only by the compat mode) and redact options of the field
+// tag, like encoding/json the string option is ignored by the types which are not booleans, numbers or strings
+func (s *Serializer) tagOptions(field *reflect.StructField) (bool, bool, bool, bool) {
+
+	var omitEmpty, omitZero, quoted, redact bool
+
+	options := strings.Split(field.Tag.Get(s.options.TagKey), strComma)
+
+	for _, option := range options[1:] {
+		switch option {
+		case tagOmitEmpty:
+			omitEmpty = true
+		case tagOmitZero:
+			omitZero = true
+		case tagString:
+			quoted = true
+		case tagRedact:
+			redact = true
+		}
+	}
+
+	if quoted {
+
+		t := field.Type
+		if t.Name() == "" && t.Kind() == reflect.Ptr {
+			t = t.Elem()
+		}
+
+		switch t.Kind() {
+		case reflect.Bool, reflect.String,
+			reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+			reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+			reflect.Float32, reflect.Float64:
+		default:
+			quoted = false
+		}
+	}
+
+	return omitEmpty, omitZero, quoted, redact
+}
+
+// marshalerEncoder - returns an encoder calling the json.Marshaler or encoding.TextMarshaler implemented by the type
+func (s *Serializer) marshalerEncoder(t reflect.Type) (EncoderFunc, bool) {
+
+	if t == numberType {
+		return s.encodeNumber, true
+	}
+
+	if t.Implements(marshalerType) {
+		return s.encodeMarshaler, true
+	}
+
+	if t.Implements(textMarshalerType) {
+		return s.encodeTextMarshaler, true
+	}
+
+	return nil, false
+}
+
+// isNil - checks if the value is a nil pointer or interface
+func (s *Serializer) isNil(v reflect.Value) bool {
+
+	return (v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface) && v.IsNil()
+}
+
+// encodeNumber - encodes a json.Number as a number literal
+func (s *Serializer) encodeNumber(dst []byte, v reflect.Value) ([]byte, error) {
+
+	number := v.String()
+	if number == "" {
+		return append(dst, strZeroNumber...), nil
+	}
+
+	if !stdjson.Valid([]byte(number)) {
+		return dst, fmt.Errorf("invalid number literal: %q", number)
+	}
+
+	return append(dst, number...), nil
+}
+
+// encodeMarshaler - encodes the value using its MarshalJSON method, the output is compacted like encoding/json does
+func (s *Serializer) encodeMarshaler(dst []byte, v reflect.Value) ([]byte, error) {
+
+	if s.isNil(v) {
+		return append(dst, serializer.Null...), nil
+	}
+
+	encoded, err := v.Interface().(stdjson.Marshaler).MarshalJSON()
+	if err != nil {
+		return dst, fmt.Errorf("error calling MarshalJSON of type %s: %s", v.Type().String(), err.Error())
+	}
+
+	var compacted bytes.Buffer
+	err = stdjson.Compact(&compacted, encoded)
+	if err != nil {
+		return dst, fmt.Errorf("error calling MarshalJSON of type %s: %s", v.Type().String(), err.Error())
+	}
+
+	var escaped bytes.Buffer
+	stdjson.HTMLEscape(&escaped, compacted.Bytes())
+
+	return append(dst, escaped.Bytes()...), nil
+}
+
+// encodeTextMarshaler - encodes the value using its MarshalText method as a string
+func (s *Serializer) encodeTextMarshaler(dst []byte, v reflect.Value) ([]byte, error) {
+
+	if s.isNil(v) {
+		return append(dst, serializer.Null...), nil
+	}
+
+	text, err := v.Interface().(encoding.TextMarshaler).MarshalText()
+	if err != nil {
+		return dst, fmt.Errorf("error calling MarshalText of type %s: %s", v.Type().String(), err.Error())
+	}
+
+	var b strings.Builder
+	s.writeCompatString(string(text), &b)
+
+	return append(dst, b.String()...), nil
+}
+
+// writeCompatString - writes a string escaped exactly like encoding/json does (control characters, invalid UTF-8,
+// the line and paragraph separators and the HTML characters)
+func (s *Serializer) writeCompatString(value string, b *strings.Builder) {
+
+	b.WriteByte(byteValueDoubleQuote)
+
+	start := 0
+
+	for i := 0; i < len(value); {
+
+		c := value[i]
+
+		if c < utf8.RuneSelf {
+
+			if c >= 0x20 && c != byteValueDoubleQuote && c != byteValueEscapeBar && (!s.options.HTMLEscape || (c != '<' && c != '>' && c != '&')) {
+				i++
+				continue
+			}
+
+			b.WriteString(value[start:i])
+
+			switch c {
+			case byteValueDoubleQuote:
+				b.WriteString(jsonEscapedDoubleQuote)
+			case byteValueEscapeBar:
+				b.WriteString(jsonEscapedEscapeBar)
+			case '\n':
+				b.WriteString(strEscapedNewLine)
+			case '\r':
+				b.WriteString(strEscapedReturn)
+			case '\t':
+				b.WriteString(strEscapedTab)
+			case '\b':
+				b.WriteString(compatBackspace)
+			case '\f':
+				b.WriteString(compatFormFeed)
+			default:
+				b.WriteString(strUnicodeEscape)
+				b.WriteByte(hexDigits[c>>4])
+				b.WriteByte(hexDigits[c&0xF])
+			}
+
+			i++
+			start = i
+
+			continue
+		}
+
+		r, size := utf8.DecodeRuneInString(value[i:])
+
+		if (r == utf8.RuneError && size == 1) || r == '\u2028' || r == '\u2029' {
+
+			b.WriteString(value[start:i])
+
+			switch r {
+			case '\u2028':
+				b.WriteString(strLineSeparator)
+			case '\u2029':
+				b.WriteString(strParagraphSeparator)
+			default:
+				b.WriteString(compatInvalidRune)
+			}
+
+			i += size
+			start = i
+
+			continue
+		}
+
+		i += size
+	}
+
+	b.WriteString(value[start:])
+	b.WriteByte(byteValueDoubleQuote)
+}
+
+// formatFloat - formats the float value like encoding/json does (shortest representation of the value bit size,
+// exponent only for very small or big values), with the configured format out of compat mode or like RFC 8785 in
+// canonical mode
+func (s *Serializer) formatFloat(value *reflect.Value) (string, error) {
+
+	if s.options.Canonical {
+		return s.formatCanonicalNumber(value.Float())
+	}
+
+	if !s.options.Compat && s.options.CustomFloatFormat() {
+		return s.options.FormatFloat(value.Float()), nil
+	}
+
+	f := value.Float()
+	if math.IsInf(f, 0) || math.IsNaN(f) {
+		return serializer.Empty, fmt.Errorf("unsupported float value: %s", strconv.FormatFloat(f, 'g', -1, 64))
+	}
+
+	return string(appendFloat(make([]byte, 0, 24), f, value.Type().Bits())), nil
+}
+
+// appendFloat - appends the shortest representation of the float for its bit size, using the exponent only for very
+// small or big values like encoding/json does
+func appendFloat(dst []byte, f float64, bits int) []byte {
+
+	format := byteFloatDecimal
+
+	if abs := math.Abs(f); abs != 0 {
+		if bits == 64 && (abs < minPlainFloat || abs >= maxPlainFloat) ||
+			bits == 32 && (float32(abs) < minPlainFloat || float32(abs) >= maxPlainFloat) {
+			format = byteFloatExponent
+		}
+	}
+
+	start := len(dst)
+	dst = strconv.AppendFloat(dst, f, format, -1, bits)
+
+	if format == byteFloatExponent {
+		// clean up e-09 to e-9
+		n := len(dst)
+		if n-start >= 4 && dst[n-4] == byteFloatExponent && dst[n-3] == '-' && dst[n-2] == '0' {
+			dst[n-2] = dst[n-1]
+			dst = dst[:n-1]
+		}
+	}
+
+	return dst
+}
+
+// isEmptyValue - checks if the value is empty by the omitempty rules
+func (s *Serializer) isEmptyValue(v *reflect.Value) bool {
+
+	switch v.Kind() {
+	case reflect.Array, reflect.Map, reflect.Slice, reflect.String:
+		return v.Len() == 0
+	case reflect.Bool:
+		return !v.Bool()
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return v.Int() == 0
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return v.Uint() == 0
+	case reflect.Float32, reflect.Float64:
+		return v.Float() == 0
+	case reflect.Interface, reflect.Ptr:
+		return v.IsNil()
+	default:
+		return false
+	}
+}
+
+// isZeroValue - checks if the value is zero by the omitzero rules (the IsZero method is used when implemented), the
+// encoding/json output is the same only from Go 1.24, the older versions ignore the omitzero option
+func (s *Serializer) isZeroValue(v *reflect.Value) bool {
+
+	if v.Type().Implements(isZeroerType) {
+		if s.isNil(*v) {
+			return true
+		}
+		return v.Interface().(interface{ IsZero() bool }).IsZero()
+	}
+
+	return v.IsZero()
+}
+
+// isOmitted - checks if the compat mode omits the property with the value
+func (s *Serializer) isOmitted(field *structField, value *reflect.Value) bool {
+
+	if !s.options.Compat {
+		return false
+	}
+
+	return (field.omitEmpty && s.isEmptyValue(value)) || (field.omitZero && s.isZeroValue(value))
+}
+
+// isOmittedConstant - checks if the compat mode omits the property from the template, the properties which can be
+// omitted must not contain variables (the template can't omit them)
+func (s *Serializer) isOmittedConstant(field *structField, value *reflect.Value, variablePaths map[string]struct{}, path string) (bool, error) {
+
+	if !s.options.Compat || (!field.omitEmpty && !field.omitZero) {
+		return false, nil
+	}
+
+	propertyPath := s.buildPath(path, field.name)
+
+	for variablePath := range variablePaths {
+		if variablePath == propertyPath || strings.HasPrefix(variablePath, propertyPath+strDot) || strings.HasPrefix(variablePath, propertyPath+strSquareBracketLeft) {
+			return false, fmt.Errorf(`the variable "%s" is inside the property "%s", which may be omitted when empty (not supported by templates in compat mode)`, variablePath, propertyPath)
+		}
+	}
+
+	return s.isOmitted(field, value), nil
+}
+
+// isWholeValue - checks if a map or array property is mapped as a single value in compat mode (or when requested by
+// the type mappings), like the nil ones (written as null) and the whole variables (which may be null)
+func (s *Serializer) isWholeValue(value *reflect.Value, variablePaths map[string]struct{}, path, name string, wholeValues bool) bool {
+
+	if !s.options.Compat && !wholeValues {
+		return false
+	}
+
+	if value.Kind() != reflect.Array && value.IsNil() {
+		return true
+	}
+
+	_, ok := variablePaths[s.buildPath(path, name)]
+
+	return ok
+}
+
+// renderQuoted - renders the value of a field with the string tag option, the value is written as a JSON string
+func (s *Serializer) renderQuoted(value *reflect.Value) (string, error) {
+
+	v := *value
+
+	for v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return serializer.Null, nil
+		}
+		v = v.Elem()
+	}
+
+	rendered, err := s.getValueFromField(nil, &v)
+	if err != nil {
+		return serializer.Empty, err
+	}
+
+	if v.Kind() == reflect.String {
+		var b strings.Builder
+		s.writeCompatString(rendered, &b)
+		return b.String(), nil
+	}
+
+	return strDoubleQuote + rendered + strDoubleQuote, nil
+}
@@ -0,0 +1,410 @@
+package json
+
+import (
+	"bytes"
+	stdjson "encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+/**
+* Has all parsing methods from the JSON serializer (the reverse of the serialization).
+* @author rnojiri
+**/
+
+// Parse - extracts the variables of a mapped JSON from the data, the keys are the variable names
+func (s *Serializer) Parse(name string, data []byte) (map[string]interface{}, error) {
+
+	m, slots, err := s.extractSlots(name, data)
+	if err != nil {
+		return nil, err
+	}
+
+	values := make(map[string]interface{}, m.numVariables)
+
+	for i, v := range m.variables {
+
+		if slots[i] == nil {
+			continue
+		}
+
+		values[v.path], err = s.decodeSlot(slots[i], v.kind)
+		if err != nil {
+			return nil, fmt.Errorf(`error parsing variable "%s": %s`, v.path, err.Error())
+		}
+	}
+
+	return values, nil
+}
+
+// ParseInto - extracts the variables of a mapped JSON from the data and sets them in the destination struct pointer
+func (s *Serializer) ParseInto(name string, data []byte, dst interface{}) error {
+
+	dv := reflect.ValueOf(dst)
+	if dv.Kind() != reflect.Ptr || dv.IsNil() || dv.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("the destination must be a non nil struct pointer")
+	}
+
+	m, slots, err := s.extractSlots(name, data)
+	if err != nil {
+		return err
+	}
+
+	root := dv.Elem()
+
+	for i, v := range m.variables {
+
+		if slots[i] == nil {
+			continue
+		}
+
+		if v.key {
+			return fmt.Errorf(`the key variable "%s" has no struct destination`, v.path)
+		}
+
+		err = s.setPath(&root, v.path, slots[i])
+		if err != nil {
+			return fmt.Errorf(`error setting variable "%s": %s`, v.path, err.Error())
+		}
+	}
+
+	return nil
+}
+
+// extractSlots - returns the raw JSON value of each variable, using the template when possible
+func (s *Serializer) extractSlots(name string, data []byte) (*mappedJSON, [][]byte, error) {
+
+	m, ok := s.getMapping(name)
+	if !ok {
+		return nil, nil, fmt.Errorf("no json mapping with name \"%s\"", name)
+	}
+
+	slots, templateErr := s.extractTemplateSlots(m, data)
+	if templateErr == nil {
+		return m, slots, nil
+	}
+
+	slots, err := s.extractGenericSlots(m, data)
+	if err != nil {
+		return nil, nil, fmt.Errorf("data does not match the mapping \"%s\" (%s) and the generic parse failed: %s", name, templateErr.Error(), err.Error())
+	}
+
+	return m, slots, nil
+}
+
+// extractTemplateSlots - jumps over the constant fragments and extracts only the variable values
+func (s *Serializer) extractTemplateSlots(m *mappedJSON, data []byte) ([][]byte, error) {
+
+	slots := make([][]byte, m.numVariables)
+	pos := 0
+
+	for i, v := range m.variables {
+
+		fragment := m.fragments[i]
+		if !bytes.HasPrefix(data[pos:], []byte(fragment)) {
+			return nil, fmt.Errorf("constant fragment mismatch at offset %d", pos)
+		}
+
+		pos += len(fragment)
+
+		start := pos
+		if v.kind == reflect.Map || v.kind == reflect.Array || v.kind == reflect.Slice {
+			start--
+		}
+
+		end, err := s.scanValue(data, start)
+		if err != nil {
+			return nil, err
+		}
+
+		slots[i] = data[start:end]
+		pos = end
+
+		if v.kind == reflect.Map || v.kind == reflect.Array || v.kind == reflect.Slice {
+			pos--
+		}
+	}
+
+	if string(data[pos:]) != m.fragments[m.numVariables] {
+		return nil, fmt.Errorf("constant fragment mismatch at offset %d", pos)
+	}
+
+	return slots, nil
+}
+
+// extractGenericSlots - parses the whole data and looks up each variable path
+func (s *Serializer) extractGenericSlots(m *mappedJSON, data []byte) ([][]byte, error) {
+
+	var tree interface{}
+
+	decoder := stdjson.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+
+	err := decoder.Decode(&tree)
+	if err != nil {
+		return nil, err
+	}
+
+	slots := make([][]byte, m.numVariables)
+
+	for i, v := range m.variables {
+
+		value, ok := s.lookupPath(tree, v.path)
+		if !ok {
+			// the optional sections may be omitted
+			if v.optional {
+				continue
+			}
+			return nil, fmt.Errorf(`variable "%s" not found`, v.path)
+		}
+
+		slots[i], err = stdjson.Marshal(value)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return slots, nil
+}
+
+// splitPath - splits a variable path in property names and array indexes ("a.b[1]" -> "a", "b", "[1]")
+func (s *Serializer) splitPath(path string) []string {
+
+	parts := []string{}
+
+	for _, property := range strings.Split(path, strDot) {
+
+		index := strings.Index(property, strSquareBracketLeft)
+		if index < 0 {
+			parts = append(parts, property)
+			continue
+		}
+
+		if index > 0 {
+			parts = append(parts, property[:index])
+		}
+
+		for _, i := range strings.SplitAfter(property[index:], strSquareBracketRight) {
+			if len(i) > 0 {
+				parts = append(parts, i)
+			}
+		}
+	}
+
+	return parts
+}
+
+// arrayIndex - returns the index from a path part like "[1]"
+func (s *Serializer) arrayIndex(part string) (int, bool) {
+
+	if !strings.HasPrefix(part, strSquareBracketLeft) || !strings.HasSuffix(part, strSquareBracketRight) {
+		return 0, false
+	}
+
+	index, err := strconv.Atoi(part[1 : len(part)-1])
+
+	return index, err == nil
+}
+
+// lookupPath - finds the value of the path in a generic parsed JSON
+func (s *Serializer) lookupPath(tree interface{}, path string) (interface{}, bool) {
+
+	current := tree
+
+	for _, part := range s.splitPath(path) {
+
+		if index, ok := s.arrayIndex(part); ok {
+
+			array, ok := current.([]interface{})
+			if !ok || index < 0 || index >= len(array) {
+				return nil, false
+			}
+
+			current = array[index]
+			continue
+		}
+
+		object, ok := current.(map[string]interface{})
+		if !ok {
+			return nil, false
+		}
+
+		current, ok = object[part]
+		if !ok {
+			return nil, false
+		}
+	}
+
+	return current, true
+}
+
+// scanValue - returns the end offset of the JSON value starting at the offset
+func (s *Serializer) scanValue(data []byte, start int) (int, error) {
+
+	if start >= len(data) {
+		return 0, fmt.Errorf("unexpected end of data at offset %d", start)
+	}
+
+	switch data[start] {
+	case byteValueDoubleQuote:
+		for i := start + 1; i < len(data); i++ {
+			if data[i] == byteValueEscapeBar {
+				i++
+			} else if data[i] == byteValueDoubleQuote {
+				return i + 1, nil
+			}
+		}
+		return 0, fmt.Errorf("unterminated string at offset %d", start)
+	case '{', '[':
+		depth := 0
+		for i := start; i < len(data); i++ {
+			switch data[i] {
+			case byteValueDoubleQuote:
+				end, err := s.scanValue(data, i)
+				if err != nil {
+					return 0, err
+				}
+				i = end - 1
+			case '{', '[':
+				depth++
+			case '}', ']':
+				depth--
+				if depth == 0 {
+					return i + 1, nil
+				}
+			}
+		}
+		return 0, fmt.Errorf("unterminated object or array at offset %d", start)
+	default:
+		i := start
+		for i < len(data) && bytes.IndexByte([]byte(",}] \t\r\n"), data[i]) < 0 {
+			i++
+		}
+		if i == start {
+			return 0, fmt.Errorf("empty value at offset %d", start)
+		}
+		return i, nil
+	}
+}
+
+// decodeSlot - decodes a raw JSON value to the go type of the variable kind
+func (s *Serializer) decodeSlot(raw []byte, kind reflect.Kind) (interface{}, error) {
+
+	switch kind {
+	case reflect.String:
+		if len(raw) >= 2 && raw[0] == byteValueDoubleQuote && bytes.IndexByte(raw, byteValueEscapeBar) < 0 {
+			return string(raw[1 : len(raw)-1]), nil
+		}
+		var value string
+		err := stdjson.Unmarshal(raw, &value)
+		return value, err
+	case reflect.Uint, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uint8, reflect.Uintptr:
+		return strconv.ParseUint(string(raw), 10, 64)
+	case reflect.Int, reflect.Int16, reflect.Int32, reflect.Int64, reflect.Int8:
+		return strconv.ParseInt(string(raw), 10, 64)
+	case reflect.Float32, reflect.Float64:
+		return strconv.ParseFloat(string(raw), 64)
+	case reflect.Bool:
+		return strconv.ParseBool(string(raw))
+	case reflect.Map:
+		value := map[string]interface{}{}
+		err := stdjson.Unmarshal(raw, &value)
+		return value, err
+	case reflect.Array, reflect.Slice:
+		value := []interface{}{}
+		err := stdjson.Unmarshal(raw, &value)
+		return value, err
+	default:
+		var value interface{}
+		err := stdjson.Unmarshal(raw, &value)
+		return value, err
+	}
+}
+
+// findField - finds the struct field with the json property name (promoted fields too), the nil embedded pointers are allocated
+func (s *Serializer) findField(v *reflect.Value, property string) (reflect.Value, bool) {
+
+	for _, field := range s.typeFields(v.Type()) {
+		if field.name == property {
+			return s.fieldByIndexAlloc(*v, field.index)
+		}
+	}
+
+	return reflect.Value{}, false
+}
+
+// setPath - decodes the raw JSON value in the destination path
+func (s *Serializer) setPath(root *reflect.Value, path string, raw []byte) error {
+
+	parts := s.splitPath(path)
+	current := *root
+
+	for i, part := range parts {
+
+		last := i == len(parts)-1
+
+		switch current.Kind() {
+		case reflect.Struct:
+
+			field, ok := s.findField(&current, part)
+			if !ok {
+				return fmt.Errorf("property not found: %s", part)
+			}
+
+			current = field
+
+		case reflect.Array, reflect.Slice:
+
+			index, ok := s.arrayIndex(part)
+			if !ok {
+				return fmt.Errorf("expected an array index: %s", part)
+			}
+
+			if current.Kind() == reflect.Slice && index >= current.Len() {
+				current.Set(reflect.AppendSlice(current, reflect.MakeSlice(current.Type(), index-current.Len()+1, index-current.Len()+1)))
+			}
+
+			if index < 0 || index >= current.Len() {
+				return fmt.Errorf("index out of range: %s", part)
+			}
+
+			current = current.Index(index)
+
+		case reflect.Map:
+
+			if !last {
+				return fmt.Errorf("only map values of basic types are supported: %s", part)
+			}
+
+			if current.IsNil() {
+				current.Set(reflect.MakeMap(current.Type()))
+			}
+
+			value := reflect.New(current.Type().Elem())
+			err := stdjson.Unmarshal(raw, value.Interface())
+			if err != nil {
+				return err
+			}
+
+			key, err := s.parseKey(part, current.Type().Key())
+			if err != nil {
+				return err
+			}
+
+			current.SetMapIndex(key, value.Elem())
+
+			return nil
+
+		default:
+			return fmt.Errorf("unexpected kind %s at: %s", current.Kind().String(), part)
+		}
+	}
+
+	if !current.CanSet() {
+		return fmt.Errorf("the destination is not settable")
+	}
+
+	return stdjson.Unmarshal(raw, current.Addr().Interface())
+}
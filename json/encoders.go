@@ -0,0 +1,113 @@
+package json
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/uol/serializer/serializer"
+)
+
+/**
+* Has the custom type encoders registry from the JSON serializer.
+* @author rnojiri
+**/
+
+// EncoderFunc - appends the JSON form of the value to the destination, the output must be a valid JSON value
+type EncoderFunc func(dst []byte, v reflect.Value) ([]byte, error)
+
+// RegisterEncoder - registers a custom encoder for the type, used by constants and variables of this type
+// (register the encoders before adding the mappings, the constants are rendered when the mapping is added)
+func (s *Serializer) RegisterEncoder(t reflect.Type, encoder EncoderFunc) error {
+
+	if t == nil {
+		return fmt.Errorf("the type is null")
+	}
+
+	if encoder == nil {
+		return fmt.Errorf("the encoder of type %s is null", t.String())
+	}
+
+	s.encodersLock.Lock()
+	s.encoders[t] = encoder
+	s.encodersLock.Unlock()
+
+	// the compiled type mappings may have rendered this type in the default way
+	s.typesLock.Lock()
+	s.types = map[reflect.Type]*mappedJSON{}
+	s.typesLock.Unlock()
+
+	// the encoded structs are not flattened anymore
+	s.fieldsLock.Lock()
+	s.fields = map[reflect.Type][]structField{}
+	s.fieldsLock.Unlock()
+
+	return nil
+}
+
+// getEncoder - returns the custom encoder of the type
+func (s *Serializer) getEncoder(t reflect.Type) (EncoderFunc, bool) {
+
+	s.encodersLock.RLock()
+	encoder, ok := s.encoders[t]
+	s.encodersLock.RUnlock()
+
+	if !ok && s.options.Compat {
+		return s.marshalerEncoder(t)
+	}
+
+	return encoder, ok
+}
+
+// hasEncoder - checks if the type has a custom encoder
+func (s *Serializer) hasEncoder(t reflect.Type) bool {
+
+	_, ok := s.getEncoder(t)
+
+	return ok
+}
+
+// encodeCustom - encodes the value using its custom encoder, returns false if the type has none
+func (s *Serializer) encodeCustom(value *reflect.Value) (string, bool, error) {
+
+	encoder, ok := s.getEncoder(value.Type())
+	if !ok {
+		return serializer.Empty, false, nil
+	}
+
+	if !value.CanInterface() {
+		return serializer.Empty, true, fmt.Errorf("the value of type %s is not accessible by its custom encoder", value.Type().String())
+	}
+
+	encoded, err := encoder(make([]byte, 0, s.bufferSize), *value)
+	if err != nil {
+		return serializer.Empty, true, err
+	}
+
+	if s.options.Canonical {
+		canonical, err := s.canonicalize(string(encoded))
+		return canonical, true, err
+	}
+
+	return string(encoded), true, nil
+}
+
+// variableKind - returns the kind of a variable of the type, custom encoded types, raw fragments and pointers are treated as any value
+func (s *Serializer) variableKind(t reflect.Type) reflect.Kind {
+
+	if s.hasEncoder(t) || s.isRaw(t) || t.Kind() == reflect.Ptr {
+		return reflect.Interface
+	}
+
+	if s.isBytes(t) {
+		return reflect.String
+	}
+
+	if s.options.Compat {
+		switch t.Kind() {
+		case reflect.Map, reflect.Array, reflect.Slice, reflect.Struct:
+			return reflect.Interface
+		}
+	}
+
+	return t.Kind()
+}
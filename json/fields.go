@@ -0,0 +1,268 @@
+package json
+
+import (
+	"reflect"
+	"sort"
+)
+
+/**
+* Has the struct fields resolution from the JSON serializer, following the encoding/json embedding rules.
+* @author rnojiri
+**/
+
+// structField - a JSON property of a struct type
+type structField struct {
+	name      string
+	index     []int
+	typ       reflect.Type
+	tagged    bool
+	dynamic   bool
+	omitEmpty bool
+	omitZero  bool
+	quoted    bool
+	redact    bool
+}
+
+// queuedStruct - an embedded struct waiting to have its fields resolved
+type queuedStruct struct {
+	typ     reflect.Type
+	index   []int
+	dynamic bool
+}
+
+// typeFields - returns the JSON properties of the struct type in field order, the fields of the flattened structs are
+// promoted like encoding/json does (the shallower wins, then the tagged one, conflicting names are dropped)
+func (s *Serializer) typeFields(t reflect.Type) []structField {
+
+	s.fieldsLock.RLock()
+	fields, ok := s.fields[t]
+	s.fieldsLock.RUnlock()
+
+	if ok {
+		return fields
+	}
+
+	current := []queuedStruct{}
+	next := []queuedStruct{{typ: t}}
+
+	count := map[reflect.Type]int{}
+	nextCount := map[reflect.Type]int{}
+	visited := map[reflect.Type]bool{}
+
+	for len(next) > 0 {
+
+		current, next = next, current[:0]
+		count, nextCount = nextCount, map[reflect.Type]int{}
+
+		for _, q := range current {
+
+			if visited[q.typ] {
+				continue
+			}
+
+			visited[q.typ] = true
+
+			for i := 0; i < q.typ.NumField(); i++ {
+
+				sf := q.typ.Field(i)
+
+				ft := sf.Type
+				if sf.Anonymous && ft.Kind() == reflect.Ptr {
+					ft = ft.Elem()
+				}
+
+				if sf.Anonymous {
+					if sf.PkgPath != "" && ft.Kind() != reflect.Struct {
+						continue
+					}
+				} else if sf.PkgPath != "" {
+					continue
+				}
+
+				name, tagged, ok := s.propertyName(&sf)
+				if !ok {
+					continue
+				}
+
+				index := make([]int, len(q.index)+1)
+				copy(index, q.index)
+				index[len(q.index)] = i
+
+				if name != "" {
+
+					omitEmpty, omitZero, quoted, redact := s.tagOptions(&sf)
+
+					fields = append(fields, structField{
+						name:      name,
+						index:     index,
+						typ:       sf.Type,
+						tagged:    tagged,
+						dynamic:   q.dynamic,
+						omitEmpty: omitEmpty,
+						omitZero:  omitZero,
+						quoted:    quoted,
+						redact:    redact,
+					})
+
+					if count[q.typ] > 1 {
+						// the same struct was embedded more than once in the same depth, the duplicate drops both
+						fields = append(fields, fields[len(fields)-1])
+					}
+
+					continue
+				}
+
+				if ft.Kind() != reflect.Struct || s.hasEncoder(ft) {
+					continue
+				}
+
+				nextCount[ft]++
+				if nextCount[ft] == 1 {
+					next = append(next, queuedStruct{
+						typ:     ft,
+						index:   index,
+						dynamic: q.dynamic || sf.Type.Kind() == reflect.Ptr,
+					})
+				}
+			}
+		}
+	}
+
+	sort.Slice(fields, func(i, j int) bool {
+
+		if fields[i].name != fields[j].name {
+			return fields[i].name < fields[j].name
+		}
+
+		if len(fields[i].index) != len(fields[j].index) {
+			return len(fields[i].index) < len(fields[j].index)
+		}
+
+		if fields[i].tagged != fields[j].tagged {
+			return fields[i].tagged
+		}
+
+		return s.indexLess(fields[i].index, fields[j].index)
+	})
+
+	dominants := make([]structField, 0, len(fields))
+
+	for i, advance := 0, 0; i < len(fields); i += advance {
+
+		for advance = 1; i+advance < len(fields); advance++ {
+			if fields[i+advance].name != fields[i].name {
+				break
+			}
+		}
+
+		if advance == 1 {
+			dominants = append(dominants, fields[i])
+			continue
+		}
+
+		// fields with the same name, depth and tagging annihilate each other
+		if len(fields[i].index) == len(fields[i+1].index) && fields[i].tagged == fields[i+1].tagged {
+			continue
+		}
+
+		dominants = append(dominants, fields[i])
+	}
+
+	sort.Slice(dominants, func(i, j int) bool {
+		return s.indexLess(dominants[i].index, dominants[j].index)
+	})
+
+	if s.options.Canonical {
+		s.sortFields(dominants)
+	}
+
+	s.fieldsLock.Lock()
+	s.fields[t] = dominants
+	s.fieldsLock.Unlock()
+
+	return dominants
+}
+
+// indexLess - compares two field index sequences
+func (s *Serializer) indexLess(a, b []int) bool {
+
+	for k, x := range a {
+
+		if k >= len(b) {
+			return false
+		}
+
+		if x != b[k] {
+			return x < b[k]
+		}
+	}
+
+	return len(a) < len(b)
+}
+
+// fieldByIndex - returns the field value by its index sequence, false is returned if an embedded pointer is nil
+func (s *Serializer) fieldByIndex(v reflect.Value, index []int) (reflect.Value, bool) {
+
+	for i, x := range index {
+
+		if i > 0 && v.Kind() == reflect.Ptr {
+			if v.IsNil() {
+				return reflect.Value{}, false
+			}
+			v = v.Elem()
+		}
+
+		v = v.Field(x)
+	}
+
+	return v, true
+}
+
+// fieldByIndexAlloc - returns the settable field value by its index sequence, allocating the nil embedded pointers
+func (s *Serializer) fieldByIndexAlloc(v reflect.Value, index []int) (reflect.Value, bool) {
+
+	for i, x := range index {
+
+		if i > 0 && v.Kind() == reflect.Ptr {
+			if v.IsNil() {
+				if !v.CanSet() {
+					return reflect.Value{}, false
+				}
+				v.Set(reflect.New(v.Type().Elem()))
+			}
+			v = v.Elem()
+		}
+
+		v = v.Field(x)
+	}
+
+	return v, true
+}
+
+// isDynamic - checks if the properties of the type depend on the values (embedded pointers may be nil and, in compat
+// mode, the empty values may be omitted)
+func (s *Serializer) isDynamic(t reflect.Type, visited map[reflect.Type]bool) bool {
+
+	if visited[t] {
+		return false
+	}
+
+	visited[t] = true
+
+	if s.hasEncoder(t) {
+		return true
+	}
+
+	for _, f := range s.typeFields(t) {
+
+		if f.dynamic || (s.options.Compat && (f.omitEmpty || f.omitZero)) {
+			return true
+		}
+
+		if f.typ.Kind() == reflect.Struct && !s.hasEncoder(f.typ) && s.isDynamic(f.typ, visited) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,51 @@
+package json
+
+import (
+	"math"
+
+	"github.com/uol/serializer/serializer"
+)
+
+/**
+* Has the functions used by the code generated serializers (cmd/serializergen).
+* @author rnojiri
+**/
+
+// AppendString - appends a string in JSON format, escaped the same way as the template serializer does
+func AppendString(dst []byte, value string) []byte {
+
+	dst = append(dst, byteValueDoubleQuote)
+
+	for i := 0; i < len(value); i++ {
+		c := value[i]
+		if c == byteValueDoubleQuote {
+			dst = append(dst, jsonEscapedDoubleQuote...)
+		} else if c == byteValueEscapeBar {
+			dst = append(dst, jsonEscapedEscapeBar...)
+		} else if c == '\n' {
+			dst = append(dst, strEscapedNewLine...)
+		} else if c == '\r' {
+			dst = append(dst, strEscapedReturn...)
+		} else if c == '\t' {
+			dst = append(dst, strEscapedTab...)
+		} else if c < 0x20 {
+			dst = append(dst, strUnicodeEscape...)
+			dst = append(dst, hexDigits[c>>4], hexDigits[c&0xF])
+		} else {
+			dst = append(dst, c)
+		}
+	}
+
+	return append(dst, byteValueDoubleQuote)
+}
+
+// AppendFloat - appends a float with the default format of the template serializer (the shortest representation of
+// the bit size, like encoding/json), NaN and infinities (rejected by the template serializer) are written as null
+func AppendFloat(dst []byte, value float64, bits int) []byte {
+
+	if math.IsInf(value, 0) || math.IsNaN(value) {
+		return append(dst, serializer.Null...)
+	}
+
+	return appendFloat(dst, value, bits)
+}
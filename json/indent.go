@@ -0,0 +1,251 @@
+package json
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/uol/serializer/serializer"
+)
+
+/**
+* Has all indented (pretty printed) serialization methods from the JSON serializer.
+* @author rnojiri
+**/
+
+const (
+	noIndentDepth  int    = -1
+	strNewLine     string = "\n"
+	strColonSpaced string = ": "
+)
+
+// indentedJSON - the indented version of a mapped JSON
+type indentedJSON struct {
+	format   string
+	prefix   string
+	indent   string
+	depths   []int
+	bare     []bool
+	segments []segment
+}
+
+// SerializeIndent - serializes a mapped JSON using the indented template, the mapping must be added with the Indent option
+func (s *Serializer) SerializeIndent(name string, parameters ...interface{}) (result string, err error) {
+
+	defer s.options.Recover(&err)
+
+	return s.serializeIndent(name, false, parameters)
+}
+
+// serializeIndent - serializes a mapped JSON using the indented template, masking the redacted properties if requested
+func (s *Serializer) serializeIndent(name string, redacted bool, parameters []interface{}) (string, error) {
+
+	m, ok := s.getMapping(name)
+	if !ok {
+		return serializer.Empty, fmt.Errorf("no json mapping with name \"%s\"", name)
+	}
+
+	m = s.view(m, redacted)
+
+	if m.indented == nil {
+		return serializer.Empty, fmt.Errorf("json mapping \"%s\" has no indented template", name)
+	}
+
+	params, supplied, err := s.buildParameters(m, parameters)
+	if err != nil {
+		return serializer.Empty, err
+	}
+
+	for i, depth := range m.indented.depths {
+
+		if depth == noIndentDepth {
+			continue
+		}
+
+		if m.indented.bare[i] {
+
+			// any JSON value, only the objects and arrays are indented
+			value := fmt.Sprintf(strStringVar, params[i])
+			if strings.HasPrefix(value, strBracketLeft) || strings.HasPrefix(value, strSquareBracketLeft) {
+				params[i], _, _ = s.indentJSON(value, m.indented.prefix, m.indented.indent, depth, nil)
+			}
+
+			continue
+		}
+
+		var b strings.Builder
+		if m.variables[i].kind == reflect.Map {
+			b.WriteString(strBracketLeft)
+			b.WriteString(params[i].(string))
+			b.WriteString(strBracketRight)
+		} else {
+			b.WriteString(strSquareBracketLeft)
+			b.WriteString(params[i].(string))
+			b.WriteString(strSquareBracketRight)
+		}
+
+		params[i], _, _ = s.indentJSON(b.String(), m.indented.prefix, m.indented.indent, depth, nil)
+	}
+
+	var result string
+
+	if present := s.presentSections(m.sections, supplied); present != nil {
+		var b strings.Builder
+		s.writeSegments(&b, m.indented.segments, params, present)
+		result = b.String()
+	} else {
+		result = fmt.Sprintf(m.indented.format, params...)
+	}
+
+	err = s.options.CheckOutputSize(len(result))
+	if err != nil {
+		return serializer.Empty, err
+	}
+
+	return result, nil
+}
+
+// mapIndented - compiles the indented template from the compact one (and its segments if there are optional sections)
+func (s *Serializer) mapIndented(m *mappedJSON, prefix, indent string, optional map[string]struct{}) error {
+
+	format, depths, bare := s.indentJSON(m.format, prefix, indent, 0, m.variables)
+
+	m.indented = &indentedJSON{
+		format: format,
+		prefix: prefix,
+		indent: indent,
+		depths: depths,
+		bare:   bare,
+	}
+
+	if len(optional) == 0 {
+		return nil
+	}
+
+	var err error
+	_, m.indented.segments, err = s.mapSections(format, m.variables, optional, prefix, indent)
+
+	return err
+}
+
+// writeNewLine - writes a new line with the prefix and the indentation of the depth
+func (s *Serializer) writeNewLine(b *strings.Builder, prefix, indent string, depth int) {
+
+	b.WriteString(strNewLine)
+	b.WriteString(prefix)
+
+	for i := 0; i < depth; i++ {
+		b.WriteString(indent)
+	}
+}
+
+// isComposite - checks if a variable written with a bare verb may hold an object or an array (the interfaces, the
+// pointers and the whole values of the compat mode and the type mappings)
+func (s *Serializer) isComposite(v *variable) bool {
+
+	switch v.kind {
+	case reflect.Interface, reflect.Ptr, reflect.Map, reflect.Array, reflect.Slice, reflect.Struct:
+		return true
+	default:
+		return false
+	}
+}
+
+// indentJSON - indents a compact JSON (or a template if the variables are given), returns the depth of the collection
+// variables and if they are written with a bare verb (any JSON value) instead of inside the brackets of the template
+func (s *Serializer) indentJSON(compact, prefix, indent string, depth int, variables []variable) (string, []int, []bool) {
+
+	var b strings.Builder
+	b.Grow(len(compact) * 2)
+
+	depths := make([]int, len(variables))
+	bare := make([]bool, len(variables))
+	varIndex := 0
+
+	for i := 0; i < len(compact); i++ {
+
+		c := compact[i]
+
+		switch c {
+		case byteValueDoubleQuote:
+
+			start := i
+			for i++; i < len(compact) && compact[i] != byteValueDoubleQuote; i++ {
+				if compact[i] == byteValueEscapeBar {
+					i++
+				}
+			}
+			b.WriteString(compact[start : i+1])
+
+		case bytePercent:
+
+			b.WriteString(compact[i : i+2])
+			if variables != nil && compact[i+1] != bytePercent {
+				depths[varIndex] = noIndentDepth
+				if compact[i+1] == strStringVar[1] && s.isComposite(&variables[varIndex]) {
+					depths[varIndex] = depth
+					bare[varIndex] = true
+				}
+				varIndex++
+			}
+			i++
+
+		case '{', '[':
+
+			closing := byte('}')
+			kind := reflect.Map
+			if c == '[' {
+				closing = ']'
+				kind = reflect.Slice
+			}
+
+			if i+1 < len(compact) && compact[i+1] == closing {
+				b.WriteByte(c)
+				b.WriteByte(closing)
+				i++
+				continue
+			}
+
+			if variables != nil && strings.HasPrefix(compact[i+1:], strStringVar+string(closing)) && varIndex < len(variables) {
+
+				varKind := variables[varIndex].kind
+				if varKind == reflect.Array {
+					varKind = reflect.Slice
+				}
+
+				if varKind == kind {
+					b.WriteString(strStringVar)
+					depths[varIndex] = depth
+					varIndex++
+					i += len(strStringVar) + 1
+					continue
+				}
+			}
+
+			b.WriteByte(c)
+			depth++
+			s.writeNewLine(&b, prefix, indent, depth)
+
+		case '}', ']':
+
+			depth--
+			s.writeNewLine(&b, prefix, indent, depth)
+			b.WriteByte(c)
+
+		case ',':
+
+			b.WriteByte(c)
+			s.writeNewLine(&b, prefix, indent, depth)
+
+		case ':':
+
+			b.WriteString(strColonSpaced)
+
+		default:
+
+			b.WriteByte(c)
+		}
+	}
+
+	return b.String(), depths, bare
+}
@@ -0,0 +1,141 @@
+package json
+
+import (
+	"encoding"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/uol/serializer/serializer"
+)
+
+/**
+* Has the map keys rendering from the JSON serializer (following the encoding/json rules).
+* @author rnojiri
+**/
+
+var (
+	textMarshalerType   = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+	textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+)
+
+// mapKey - a map key and its property name
+type mapKey struct {
+	value reflect.Value
+	name  string
+}
+
+// mapKeys - returns the map keys and their property names, sorted if the option is set
+func (s *Serializer) mapKeys(value *reflect.Value) ([]mapKey, error) {
+
+	keys := make([]mapKey, value.Len())
+
+	it := value.MapRange()
+	for i := 0; it.Next(); i++ {
+
+		name, err := s.keyName(it.Key())
+		if err != nil {
+			return nil, err
+		}
+
+		keys[i] = mapKey{value: it.Key(), name: name}
+	}
+
+	if s.options.Canonical {
+		sort.Slice(keys, func(i, j int) bool {
+			return s.utf16Less(keys[i].name, keys[j].name)
+		})
+	} else if s.options.SortedKeys {
+		sort.Slice(keys, func(i, j int) bool {
+			return keys[i].name < keys[j].name
+		})
+	}
+
+	return keys, nil
+}
+
+// keyName - returns the property name of a map key, string kinds are used directly, followed by
+// encoding.TextMarshaler implementations and integers
+func (s *Serializer) keyName(key reflect.Value) (string, error) {
+
+	if key.Kind() == reflect.String {
+		return key.String(), nil
+	}
+
+	if key.Type().Implements(textMarshalerType) {
+
+		if key.Kind() == reflect.Ptr && key.IsNil() {
+			return serializer.Empty, nil
+		}
+
+		text, err := key.Interface().(encoding.TextMarshaler).MarshalText()
+		if err != nil {
+			return serializer.Empty, err
+		}
+
+		return string(text), nil
+	}
+
+	switch key.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(key.Int(), 10), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return strconv.FormatUint(key.Uint(), 10), nil
+	default:
+		return serializer.Empty, fmt.Errorf("map key type not supported: %s", key.Type().String())
+	}
+}
+
+// renderKey - renders a map key as a JSON string, used by the key variables
+func (s *Serializer) renderKey(key *reflect.Value) (string, error) {
+
+	name, err := s.keyName(*key)
+	if err != nil {
+		return serializer.Empty, err
+	}
+
+	var b strings.Builder
+	b.Grow(len(name) + 2)
+	s.writeStringValue(name, &b)
+
+	return b.String(), nil
+}
+
+// parseKey - parses a property name to a map key of the type (the reverse of keyName)
+func (s *Serializer) parseKey(name string, t reflect.Type) (reflect.Value, error) {
+
+	if t.Kind() == reflect.String {
+		return reflect.ValueOf(name).Convert(t), nil
+	}
+
+	if reflect.PtrTo(t).Implements(textUnmarshalerType) {
+
+		key := reflect.New(t)
+
+		err := key.Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(name))
+		if err != nil {
+			return reflect.Value{}, err
+		}
+
+		return key.Elem(), nil
+	}
+
+	switch t.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(name, 10, t.Bits())
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		return reflect.ValueOf(n).Convert(t), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		n, err := strconv.ParseUint(name, 10, t.Bits())
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		return reflect.ValueOf(n).Convert(t), nil
+	default:
+		return reflect.Value{}, fmt.Errorf("map key type not supported: %s", t.String())
+	}
+}
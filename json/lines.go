@@ -0,0 +1,85 @@
+package json
+
+import (
+	"io"
+
+	"github.com/uol/serializer/serializer"
+)
+
+/**
+* Has all JSON Lines (newline delimited JSON) serialization methods from the JSON serializer.
+* @author rnojiri
+**/
+
+const (
+	byteNewLine byte = '\n'
+)
+
+// SerializeLines - serializes the items as JSON Lines, one mapped JSON per line (each one ended by a new line)
+func (s *Serializer) SerializeLines(items ...*ArrayItem) (string, error) {
+
+	if len(items) == 0 {
+		return serializer.Empty, nil
+	}
+
+	result, err := s.AppendLines(make([]byte, 0, s.bufferSize*len(items)), items...)
+	if err != nil {
+		return serializer.Empty, err
+	}
+
+	return string(result), nil
+}
+
+// AppendLines - appends the items as JSON Lines to the destination
+func (s *Serializer) AppendLines(dst []byte, items ...*ArrayItem) ([]byte, error) {
+
+	for i := 0; i < len(items); i++ {
+
+		line, err := s.serializeItem(items[i], false)
+		if err != nil {
+			return dst, err
+		}
+
+		dst = append(dst, line...)
+		dst = append(dst, byteNewLine)
+	}
+
+	return dst, nil
+}
+
+// WriteLines - writes the items as JSON Lines to the writer
+func (s *Serializer) WriteLines(w io.Writer, items ...*ArrayItem) error {
+
+	result, err := s.AppendLines(make([]byte, 0, s.bufferSize*len(items)), items...)
+	if err != nil {
+		return err
+	}
+
+	_, err = w.Write(result)
+
+	return err
+}
+
+// StreamLines - writes each item received from the channel as a JSON line until the channel is closed,
+// after an error the remaining items are discarded (the channel is still consumed until closed) and the first error is returned
+func (s *Serializer) StreamLines(w io.Writer, items <-chan *ArrayItem) error {
+
+	var err error
+	buffer := make([]byte, 0, s.bufferSize)
+
+	for item := range items {
+
+		if err != nil {
+			continue
+		}
+
+		buffer, err = s.AppendLines(buffer[:0], item)
+		if err != nil {
+			continue
+		}
+
+		_, err = w.Write(buffer)
+	}
+
+	return err
+}
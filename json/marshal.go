@@ -0,0 +1,176 @@
+package json
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/uol/serializer/serializer"
+)
+
+/**
+* Has the type keyed serialization methods from the JSON serializer.
+* @author rnojiri
+**/
+
+// Marshal - serializes a struct using a template compiled from its type on the first call, all fields are variables
+// (pointers are followed to the struct and nil pointers are written as null)
+func (s *Serializer) Marshal(item interface{}) (result string, err error) {
+
+	defer s.options.Recover(&err)
+
+	return s.marshal(item, false)
+}
+
+// marshal - serializes a struct using the template of its type, masking the redacted fields if requested
+func (s *Serializer) marshal(item interface{}, redacted bool) (string, error) {
+
+	if serializer.InterfaceHasZeroValue(item) {
+		return serializer.Null, nil
+	}
+
+	v := reflect.ValueOf(item)
+	for v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			return serializer.Null, nil
+		}
+		v = v.Elem()
+	}
+
+	if v.Kind() != reflect.Struct {
+		return serializer.Empty, fmt.Errorf("only structs can be marshalled, found kind: %s", v.Kind().String())
+	}
+
+	m, err := s.typeMapping(v.Type())
+	if err != nil {
+		return serializer.Empty, err
+	}
+
+	if m.dynamic {
+
+		if (redacted || s.options.AlwaysRedact) && s.hasRedactedFields(v.Type()) {
+			return serializer.Empty, fmt.Errorf("the redacted fields of type %s can't be masked (its properties depend on the values)", v.Type().String())
+		}
+
+		// the properties depend on the values, they are rendered without the template
+		result, err := s.renderTree(&v, 0)
+		if err != nil {
+			return serializer.Empty, err
+		}
+		return result, s.options.CheckOutputSize(len(result))
+	}
+
+	params := make([]interface{}, 0, m.numVariables)
+
+	err = s.collectVariables(&v, &params)
+	if err != nil {
+		return serializer.Empty, err
+	}
+
+	m = s.view(m, redacted)
+	s.maskParameters(m, params)
+
+	return s.execute(m, params, nil)
+}
+
+// typeMapping - returns the cached mapping for the type or compiles a new one
+func (s *Serializer) typeMapping(t reflect.Type) (*mappedJSON, error) {
+
+	s.typesLock.RLock()
+	m, ok := s.types[t]
+	s.typesLock.RUnlock()
+
+	if ok {
+		return m, nil
+	}
+
+	if s.isDynamic(t, map[reflect.Type]bool{}) {
+
+		m = &mappedJSON{dynamic: true}
+
+	} else {
+
+		variablePaths := map[string]struct{}{}
+		s.mapTypeVariables(t, variablePaths, serializer.Empty)
+
+		var err error
+		m, err = s.mapJSON(reflect.Zero(t).Interface(), variablePaths, true)
+		if err != nil {
+			return nil, err
+		}
+
+		err = s.mapRedacted(m, nil)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	s.typesLock.Lock()
+	s.types[t] = m
+	s.typesLock.Unlock()
+
+	return m, nil
+}
+
+// mapTypeVariables - adds the path of all non struct json properties of the type as variables
+func (s *Serializer) mapTypeVariables(t reflect.Type, variablePaths map[string]struct{}, path string) {
+
+	for _, field := range s.typeFields(t) {
+
+		currentPath := s.buildPath(path, field.name)
+
+		if field.typ.Kind() == reflect.Struct && !s.hasEncoder(field.typ) {
+			s.mapTypeVariables(field.typ, variablePaths, currentPath)
+			continue
+		}
+
+		variablePaths[currentPath] = struct{}{}
+	}
+}
+
+// collectVariables - renders the struct values in the same order the template variables were mapped
+func (s *Serializer) collectVariables(v *reflect.Value, params *[]interface{}) error {
+
+	for _, field := range s.typeFields(v.Type()) {
+
+		fv, ok := s.fieldByIndex(*v, field.index)
+		if !ok {
+			continue
+		}
+
+		if field.typ.Kind() == reflect.Struct && !s.hasEncoder(field.typ) {
+			err := s.collectVariables(&fv, params)
+			if err != nil {
+				return err
+			}
+			continue
+		}
+
+		if s.options.Compat && field.quoted {
+			quoted, err := s.renderQuoted(&fv)
+			if err != nil {
+				return err
+			}
+			*params = append(*params, rawValue(quoted))
+			continue
+		}
+
+		var rendered interface{}
+		var err error
+
+		switch fv.Kind() {
+		case reflect.Map, reflect.Array, reflect.Slice:
+			// whole values in the type mappings, like encoding/json the nil ones are written as null
+			rendered, err = s.renderAny(&fv)
+		default:
+			rendered, err = s.renderVariable(&fv)
+		}
+
+		if err != nil {
+			return err
+		}
+
+		*params = append(*params, rendered)
+	}
+
+	return nil
+}
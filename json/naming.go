@@ -0,0 +1,128 @@
+package json
+
+import (
+	"reflect"
+	"strings"
+	"unicode"
+)
+
+/**
+* Has the property naming strategies used by the JSON serializer.
+* @author rnojiri
+**/
+
+// NamingStrategy - returns the property name of a struct field without tag
+type NamingStrategy func(fieldName string) string
+
+// FieldName - uses the Go field name as is
+func FieldName(fieldName string) string {
+
+	return fieldName
+}
+
+// CamelCase - converts the field name to camelCase (UserID -> userID, HTTPServer -> httpServer)
+func CamelCase(fieldName string) string {
+
+	words := splitWords(fieldName)
+	if len(words) == 0 {
+		return fieldName
+	}
+
+	var b strings.Builder
+	b.Grow(len(fieldName))
+	b.WriteString(strings.ToLower(words[0]))
+
+	for _, word := range words[1:] {
+		b.WriteString(word)
+	}
+
+	return b.String()
+}
+
+// SnakeCase - converts the field name to snake_case (UserID -> user_id, HTTPServer -> http_server)
+func SnakeCase(fieldName string) string {
+
+	return strings.ToLower(strings.Join(splitWords(fieldName), "_"))
+}
+
+// KebabCase - converts the field name to kebab-case (UserID -> user-id, HTTPServer -> http-server)
+func KebabCase(fieldName string) string {
+
+	return strings.ToLower(strings.Join(splitWords(fieldName), "-"))
+}
+
+// splitWords - splits a Go identifier in words, acronyms are kept together (HTTPServerID -> HTTP, Server, ID)
+func splitWords(name string) []string {
+
+	runes := []rune(name)
+	words := []string{}
+	start := 0
+
+	for i := 1; i < len(runes); i++ {
+
+		current := runes[i]
+		previous := runes[i-1]
+
+		lowerToUpper := unicode.IsUpper(current) && !unicode.IsUpper(previous) && previous != '_'
+		acronymEnd := unicode.IsUpper(previous) && unicode.IsUpper(current) && i+1 < len(runes) && unicode.IsLower(runes[i+1])
+
+		if current == '_' {
+			if i > start {
+				words = append(words, string(runes[start:i]))
+			}
+			start = i + 1
+			continue
+		}
+
+		if lowerToUpper || acronymEnd {
+			if i > start {
+				words = append(words, string(runes[start:i]))
+			}
+			start = i
+		}
+	}
+
+	if start < len(runes) {
+		words = append(words, string(runes[start:]))
+	}
+
+	return words
+}
+
+// propertyName - returns the property name of the struct field, if it was named by the tag and if it's a property,
+// untagged fields are named by the naming strategy (if set), an empty name means the field is flattened when it's a struct
+func (s *Serializer) propertyName(field *reflect.StructField) (string, bool, bool) {
+
+	tag, ok := field.Tag.Lookup(s.options.TagKey)
+	if ok {
+
+		if tag == "-" {
+			return "", false, false
+		}
+
+		name := strings.Split(tag, strComma)[0]
+		if name != "" {
+			return name, true, true
+		}
+	}
+
+	if field.Anonymous {
+		t := field.Type
+		if t.Kind() == reflect.Ptr {
+			t = t.Elem()
+		}
+		if t.Kind() == reflect.Struct && !s.hasEncoder(t) {
+			return "", false, true
+		}
+	}
+
+	if s.options.NamingStrategy == nil {
+		if ok {
+			// tagged only with options, the field name is used like encoding/json does
+			return field.Name, false, true
+		}
+		return "", false, true
+	}
+
+	return s.options.NamingStrategy(field.Name), false, true
+}
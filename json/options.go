@@ -0,0 +1,210 @@
+package json
+
+import "github.com/uol/serializer/serializer"
+
+/**
+* Has all options used by the JSON serializer.
+* @author rnojiri
+**/
+
+// mappingOptions - the options used to compile a mapping
+type mappingOptions struct {
+	variables []string
+	required  []string
+	optional  []string
+	redact    []string
+	indented  bool
+	prefix    string
+	indent    string
+}
+
+// MappingOption - an option used when adding a new mapping
+type MappingOption func(options *mappingOptions)
+
+// Variables - sets the paths of the variables, the values from the mapped item are used as defaults
+func Variables(paths ...string) MappingOption {
+
+	return func(options *mappingOptions) {
+		options.variables = append(options.variables, paths...)
+	}
+}
+
+// Required - sets the paths of the variables which must always be supplied on serialization (no default is used)
+func Required(paths ...string) MappingOption {
+
+	return func(options *mappingOptions) {
+		options.variables = append(options.variables, paths...)
+		options.required = append(options.required, paths...)
+	}
+}
+
+// Optional - sets the paths of the properties (struct fields or map keys) omitted when none of their variables are
+// supplied on serialization (or all of them are supplied as Absent)
+func Optional(paths ...string) MappingOption {
+
+	return func(options *mappingOptions) {
+		options.optional = append(options.optional, paths...)
+	}
+}
+
+// Redact - sets the paths of the properties masked by the redacted serialization (besides the fields tagged with the
+// redact option), constants and whole variables can be redacted
+func Redact(paths ...string) MappingOption {
+
+	return func(options *mappingOptions) {
+		options.redact = append(options.redact, paths...)
+	}
+}
+
+// Indent - compiles an indented template too (used by SerializeIndent), each line begins with the prefix followed by the indent copies
+func Indent(prefix, indent string) MappingOption {
+
+	return func(options *mappingOptions) {
+		options.indented = true
+		options.prefix = prefix
+		options.indent = indent
+	}
+}
+
+// Options - the JSON serializer options
+type Options struct {
+	serializer.Options
+	SortedKeys     bool
+	HTMLEscape     bool
+	RawValidation  bool
+	TagKey         string
+	NamingStrategy NamingStrategy
+	Compat         bool
+	Canonical      bool
+	Redaction      RedactFunc
+	AlwaysRedact   bool
+}
+
+// Option - an option used when creating a new serializer
+type Option func(options *Options)
+
+// WithBufferSize - sets the initial buffer size
+func WithBufferSize(size int) Option {
+
+	return func(options *Options) {
+		options.BufferSize = size
+	}
+}
+
+// WithSortedKeys - writes the map keys sorted, making the output deterministic
+func WithSortedKeys() Option {
+
+	return func(options *Options) {
+		options.SortedKeys = true
+	}
+}
+
+// WithHTMLEscape - escapes the characters <, > and & inside strings, as encoding/json does
+func WithHTMLEscape() Option {
+
+	return func(options *Options) {
+		options.HTMLEscape = true
+	}
+}
+
+// WithRawValidation - validates the raw JSON fragments before inserting them
+func WithRawValidation() Option {
+
+	return func(options *Options) {
+		options.RawValidation = true
+	}
+}
+
+// WithTagKey - sets the struct tag key read to name the properties ("json" by default)
+func WithTagKey(key string) Option {
+
+	return func(options *Options) {
+		options.TagKey = key
+	}
+}
+
+// WithNamingStrategy - sets how the exported fields without tag are named (they are not properties by default)
+func WithNamingStrategy(strategy NamingStrategy) Option {
+
+	return func(options *Options) {
+		options.NamingStrategy = strategy
+	}
+}
+
+// WithCompat - writes the same output of encoding/json (key order, escaping, float format, nil values and the
+// omitempty, omitzero and string tag options), overriding the options which could change it (the omitzero option
+// matches encoding/json only from Go 1.24)
+func WithCompat() Option {
+
+	return func(options *Options) {
+		options.Compat = true
+	}
+}
+
+// WithCanonical - writes the canonical form of RFC 8785 (JSON Canonicalization Scheme): properties and map keys sorted
+// by their UTF-16 code units, numbers formatted like ECMAScript does and minimal string escaping, the raw fragments
+// and the custom encoders outputs are canonicalized too
+func WithCanonical() Option {
+
+	return func(options *Options) {
+		options.Canonical = true
+	}
+}
+
+// WithRedaction - sets the function masking the redacted properties (MaskValue by default)
+func WithRedaction(redaction RedactFunc) Option {
+
+	return func(options *Options) {
+		options.Redaction = redaction
+	}
+}
+
+// WithAlwaysRedact - masks the redacted properties in all serializations, not only in the Redacted view
+func WithAlwaysRedact() Option {
+
+	return func(options *Options) {
+		options.AlwaysRedact = true
+	}
+}
+
+// WithFloatFormat - sets the format and precision of the floats (see strconv.FormatFloat), by default they are
+// written like encoding/json does (shortest representation, exponent only for very small or big values)
+func WithFloatFormat(format byte, precision int) Option {
+
+	return func(options *Options) {
+		options.FloatFormat = format
+		options.FloatPrecision = precision
+	}
+}
+
+// WithNullPolicy - sets how the null variable values are handled
+func WithNullPolicy(policy serializer.NullPolicy) Option {
+
+	return func(options *Options) {
+		options.NullPolicy = policy
+	}
+}
+
+// WithPanicPolicy - sets how the unexpected panics are handled
+func WithPanicPolicy(policy serializer.PanicPolicy) Option {
+
+	return func(options *Options) {
+		options.PanicPolicy = policy
+	}
+}
+
+// WithLogger - sets the logger used to report the recovered panics
+func WithLogger(logger serializer.Logger) Option {
+
+	return func(options *Options) {
+		options.Logger = logger
+	}
+}
+
+// WithMaxOutputSize - sets the maximum size in bytes of a serialized output, bigger ones return an error
+func WithMaxOutputSize(size int) Option {
+
+	return func(options *Options) {
+		options.MaxOutputSize = size
+	}
+}
@@ -0,0 +1,66 @@
+package json
+
+import (
+	"sync"
+
+	"github.com/uol/serializer/serializer"
+)
+
+/**
+* Has the parallel serialization methods from the JSON serializer.
+* @author rnojiri
+**/
+
+// SerializeArrayParallel - serializes an array of jsons splitting the items in shards serialized concurrently,
+// the output is the same of SerializeArray
+func (s *Serializer) SerializeArrayParallel(workers int, items ...*ArrayItem) (string, error) {
+
+	numItems := len(items)
+	if workers <= 1 || numItems < 2 {
+		return s.SerializeArray(items...)
+	}
+
+	shards := serializer.Shards(workers, numItems)
+	jsons := make([]string, numItems)
+	shardSizes := make([]int, len(shards))
+	shardErrors := make([]error, len(shards))
+
+	var wg sync.WaitGroup
+	wg.Add(len(shards))
+
+	for shard := range shards {
+
+		go func(shard int) {
+
+			defer wg.Done()
+
+			for i := shards[shard][0]; i < shards[shard][1]; i++ {
+
+				var err error
+				jsons[i], err = s.serializeItem(items[i], false)
+				if err != nil {
+					shardErrors[shard] = err
+					return
+				}
+
+				shardSizes[shard] += len(jsons[i])
+			}
+
+		}(shard)
+	}
+
+	wg.Wait()
+
+	totalSize := 0
+
+	for shard := range shards {
+
+		if shardErrors[shard] != nil {
+			return serializer.Empty, shardErrors[shard]
+		}
+
+		totalSize += shardSizes[shard]
+	}
+
+	return s.joinArray(jsons, totalSize)
+}
@@ -17,34 +17,105 @@ import (
 // Add - adds a new JSON mapping
 func (s *Serializer) Add(name string, item interface{}, variablePath ...string) error {
 
+	return s.AddWithOptions(name, item, Variables(variablePath...))
+}
+
+// AddWithOptions - adds a new JSON mapping configured by the options
+func (s *Serializer) AddWithOptions(name string, item interface{}, options ...MappingOption) error {
+
+	mo := mappingOptions{}
+	for _, option := range options {
+		option(&mo)
+	}
+
 	variablePathMap := map[string]struct{}{}
-	if len(variablePath) > 0 {
-		for _, path := range variablePath {
-			variablePathMap[path] = struct{}{}
+	for _, path := range mo.variables {
+		variablePathMap[path] = struct{}{}
+	}
+
+	m, err := s.mapJSON(item, variablePathMap, false)
+	if err != nil {
+		return err
+	}
+
+	for _, path := range mo.required {
+
+		index, ok := m.variableMap[path]
+		if !ok {
+			return fmt.Errorf(`required variable "%s" does not exist`, path)
 		}
+
+		m.variables[index].required = true
+		m.numRequired++
 	}
 
-	m, err := s.mapJSON(item, variablePathMap)
+	if len(mo.optional) > 0 {
+
+		optionalMap := map[string]struct{}{}
+		for _, path := range mo.optional {
+			optionalMap[path] = struct{}{}
+		}
+
+		m.sections, m.segments, err = s.mapSections(m.format, m.variables, optionalMap, serializer.Empty, serializer.Empty)
+		if err != nil {
+			return err
+		}
+
+		for _, sec := range m.sections {
+			for i := sec.firstVar; i < sec.lastVar; i++ {
+				m.variables[i].optional = true
+			}
+		}
+
+		if mo.indented {
+			err = s.mapIndented(m, mo.prefix, mo.indent, optionalMap)
+			if err != nil {
+				return err
+			}
+		}
+
+	} else if mo.indented {
+
+		err = s.mapIndented(m, mo.prefix, mo.indent, nil)
+		if err != nil {
+			return err
+		}
+	}
+
+	err = s.mapRedacted(m, mo.redact)
 	if err != nil {
 		return err
 	}
 
+	s.mappingLock.Lock()
 	s.mapping[name] = m
+	s.mappingLock.Unlock()
 
 	return nil
 }
 
-// mapJSON - maps a new JSON struct
-func (s *Serializer) mapJSON(item interface{}, variablePaths map[string]struct{}) (*mappedJSON, error) {
+// getMapping - returns the named mapping
+func (s *Serializer) getMapping(name string) (*mappedJSON, bool) {
 
-	varSequence := []string{}
+	s.mappingLock.RLock()
+	m, ok := s.mapping[name]
+	s.mappingLock.RUnlock()
+
+	return m, ok
+}
+
+// mapJSON - maps a new JSON struct, the map and array variables are mapped as whole values (written as null when nil)
+// if requested
+func (s *Serializer) mapJSON(item interface{}, variablePaths map[string]struct{}, wholeValues bool) (*mappedJSON, error) {
+
+	varSequence := []variable{}
 
 	var b strings.Builder
 	b.Grow(s.bufferSize)
 
 	b.WriteString(strBracketLeft)
 
-	err := s.mapStruct(item, &b, &varSequence, variablePaths, serializer.Empty)
+	err := s.mapStruct(reflect.ValueOf(item), &b, &varSequence, variablePaths, serializer.Empty, wholeValues)
 	if err != nil {
 		return nil, err
 	}
@@ -52,8 +123,8 @@ func (s *Serializer) mapJSON(item interface{}, variablePaths map[string]struct{}
 	b.WriteString(strBracketRight)
 
 	variableMap := map[string]int{}
-	for i, variable := range varSequence {
-		variableMap[variable] = i
+	for i, v := range varSequence {
+		variableMap[v.path] = i
 	}
 
 	return &mappedJSON{
@@ -61,77 +132,111 @@ func (s *Serializer) mapJSON(item interface{}, variablePaths map[string]struct{}
 		formatSize:   b.Len(),
 		numVariables: len(varSequence),
 		variableMap:  variableMap,
+		variables:    varSequence,
+		fragments:    s.splitFormat(b.String()),
+		item:         item,
+		paths:        variablePaths,
+		size:         serializer.NewAdaptiveSize(b.Len()),
 	}, nil
 }
 
 // writeMapInStringFormat - writes the map string format
-func (s *Serializer) writeMapInStringFormat(field *reflect.StructField, value *reflect.Value, b *strings.Builder, varSequence *[]string, variablePaths map[string]struct{}, path string) (bool, error) {
+func (s *Serializer) writeMapInStringFormat(name string, value *reflect.Value, b *strings.Builder, varSequence *[]variable, variablePaths map[string]struct{}, path string) error {
 
-	keep, variableType, currentPath := s.fieldToProperty(field, b, varSequence, variablePaths, path)
-	if !keep {
-		return false, nil
-	}
+	variableType, currentPath := s.fieldToProperty(name, value.Type(), b, varSequence, variablePaths, path)
 
 	if variableType == propertyVariable {
 		b.WriteString(strFmtStringInBrackets)
-		return true, nil
+		return s.setDefaultValue(value, varSequence)
 	}
 
 	b.WriteString(strBracketLeft)
-	it := value.MapRange()
+	keys, err := s.mapKeys(value)
+	if err != nil {
+		return err
+	}
 
-	hasNext := it.Next()
-	for hasNext {
+	for i, k := range keys {
 
-		key := it.Key().String()
-		keyPath := s.buildPath(currentPath, key)
+		keyPath := s.buildPath(currentPath, k.name)
 
-		s.writePropertyString(key, b)
+		err = s.writeMapKey(&k, b, varSequence, variablePaths, currentPath)
+		if err != nil {
+			return err
+		}
 
-		val := it.Value()
+		val := value.MapIndex(k.value)
 
 		if _, ok := variablePaths[keyPath]; ok {
 
-			formatSymbol, err := s.getFormatSymbol(val.Type().Kind())
+			formatSymbol, err := s.getFormatSymbol(val.Type())
 			if err != nil {
-				return false, err
+				return err
 			}
 
 			b.WriteString(formatSymbol)
-			*varSequence = append(*varSequence, keyPath)
+			*varSequence = append(*varSequence, variable{path: keyPath, kind: s.variableKind(val.Type())})
+
+			err = s.setDefaultValue(&val, varSequence)
+			if err != nil {
+				return err
+			}
 
 		} else {
 
 			strVal, err := s.getValueFromField(nil, &val)
 			if err != nil {
-				return false, err
+				return err
 			}
 
-			b.WriteString(strVal)
+			b.WriteString(s.escapeFormat(strVal))
 		}
 
-		hasNext = it.Next()
-		if hasNext {
+		if i < len(keys)-1 {
 			b.WriteString(strComma)
 		}
 	}
 
 	b.WriteString(strBracketRight)
 
-	return true, nil
+	return nil
 }
 
-// writeArrayInStringFormat - writes in array string format
-func (s *Serializer) writeArrayInStringFormat(field *reflect.StructField, value *reflect.Value, b *strings.Builder, varSequence *[]string, variablePaths map[string]struct{}, path string) (bool, error) {
+// writeMapKey - writes the map key as a property name, or as a key variable if its path ("tags.@name") was given
+func (s *Serializer) writeMapKey(k *mapKey, b *strings.Builder, varSequence *[]variable, variablePaths map[string]struct{}, path string) error {
+
+	keyVariablePath := s.buildPath(path, strKeyVariable+k.name)
+
+	if _, ok := variablePaths[keyVariablePath]; !ok {
+		s.writePropertyString(k.name, b)
+		return nil
+	}
+
+	if s.options.Compat || s.options.Canonical {
+		return fmt.Errorf(`the key variable "%s" is not supported in compat or canonical mode (the keys must be sorted)`, keyVariablePath)
+	}
 
-	keep, variableType, currentPath := s.fieldToProperty(field, b, varSequence, variablePaths, path)
-	if !keep {
-		return false, nil
+	rendered, err := s.renderKey(&k.value)
+	if err != nil {
+		return err
 	}
 
+	b.WriteString(strStringVar)
+	b.WriteString(strColon)
+
+	*varSequence = append(*varSequence, variable{path: keyVariablePath, kind: reflect.String, key: true, defaultValue: rendered})
+
+	return nil
+}
+
+// writeArrayInStringFormat - writes in array string format
+func (s *Serializer) writeArrayInStringFormat(name string, value *reflect.Value, b *strings.Builder, varSequence *[]variable, variablePaths map[string]struct{}, path string) error {
+
+	variableType, currentPath := s.fieldToProperty(name, value.Type(), b, varSequence, variablePaths, path)
+
 	if variableType == propertyVariable {
 		b.WriteString(strFmtStringInSqBrackets)
-		return true, nil
+		return s.setDefaultValue(value, varSequence)
 	}
 
 	arraySize := value.Len()
@@ -152,22 +257,27 @@ func (s *Serializer) writeArrayInStringFormat(field *reflect.StructField, value
 
 		if _, ok := variablePaths[indexBuilder.String()]; ok {
 
-			formatSymbol, err := s.getFormatSymbol(val.Type().Kind())
+			formatSymbol, err := s.getFormatSymbol(val.Type())
 			if err != nil {
-				return false, err
+				return err
 			}
 
 			b.WriteString(formatSymbol)
-			*varSequence = append(*varSequence, indexBuilder.String())
+			*varSequence = append(*varSequence, variable{path: indexBuilder.String(), kind: s.variableKind(val.Type())})
+
+			err = s.setDefaultValue(&val, varSequence)
+			if err != nil {
+				return err
+			}
 
 		} else {
 
 			strVal, err := s.getValueFromField(nil, &val)
 			if err != nil {
-				return false, err
+				return err
 			}
 
-			b.WriteString(strVal)
+			b.WriteString(s.escapeFormat(strVal))
 		}
 
 		indexBuilder.Reset()
@@ -179,99 +289,157 @@ func (s *Serializer) writeArrayInStringFormat(field *reflect.StructField, value
 
 	b.WriteString(strSquareBracketRight)
 
-	return true, nil
+	return nil
 }
 
 // mapStruct - maps all variables contained in the JSON struct
-func (s *Serializer) mapStruct(item interface{}, b *strings.Builder, varSequence *[]string, variablePaths map[string]struct{}, path string) error {
+func (s *Serializer) mapStruct(value reflect.Value, b *strings.Builder, varSequence *[]variable, variablePaths map[string]struct{}, path string, wholeValues bool) error {
 
-	v := reflect.ValueOf(item)
-	t := reflect.TypeOf(item)
-	numFields := t.NumField()
+	written := false
 
-	for i := 0; i < numFields; i++ {
+	for _, field := range s.typeFields(value.Type()) {
 
-		field := t.Field(i)
+		fv, ok := s.fieldByIndex(value, field.index)
+		if !ok {
+			continue
+		}
 
-		if field.Type.Kind() == reflect.Struct {
+		omitted, err := s.isOmittedConstant(&field, &fv, variablePaths, path)
+		if err != nil {
+			return err
+		}
 
-			isSubObject, _, currentPath := s.fieldToProperty(&field, b, varSequence, variablePaths, path)
-			if isSubObject {
-				b.WriteString(strBracketLeft)
-			}
+		if omitted {
+			continue
+		}
 
-			x := v.Field(i).Interface()
-			err := s.mapStruct(x, b, varSequence, variablePaths, currentPath)
+		if written {
+			b.WriteString(strComma)
+		}
+
+		written = true
+
+		kind := field.typ.Kind()
+		encoded := s.hasEncoder(field.typ)
+
+		if kind == reflect.Struct && !encoded {
+
+			_, currentPath := s.fieldToProperty(field.name, field.typ, b, varSequence, variablePaths, path)
+
+			b.WriteString(strBracketLeft)
+
+			err := s.mapStruct(fv, b, varSequence, variablePaths, currentPath, wholeValues)
 			if err != nil {
 				return err
 			}
 
-			if isSubObject {
-				b.WriteString(strBracketRight)
-			}
-
-			if i < numFields-1 {
-				b.WriteString(strComma)
-			}
+			b.WriteString(strBracketRight)
 
 			continue
 
-		} else if field.Type.Kind() == reflect.Map {
+		} else if kind == reflect.Map && !encoded && !s.isWholeValue(&fv, variablePaths, path, field.name, wholeValues) {
 
-			fv := v.Field(i)
-			s.writeMapInStringFormat(&field, &fv, b, varSequence, variablePaths, path)
-			if i < numFields-1 {
-				b.WriteString(strComma)
+			err := s.writeMapInStringFormat(field.name, &fv, b, varSequence, variablePaths, path)
+			if err != nil {
+				return err
 			}
 
 			continue
 
-		} else if field.Type.Kind() == reflect.Array || field.Type.Kind() == reflect.Slice {
+		} else if (kind == reflect.Array || kind == reflect.Slice) && !encoded && !s.isBytes(field.typ) && !s.isWholeValue(&fv, variablePaths, path, field.name, wholeValues) {
 
-			fv := v.Field(i)
-			s.writeArrayInStringFormat(&field, &fv, b, varSequence, variablePaths, path)
-			if i < numFields-1 {
-				b.WriteString(strComma)
+			err := s.writeArrayInStringFormat(field.name, &fv, b, varSequence, variablePaths, path)
+			if err != nil {
+				return err
 			}
 
 			continue
 		}
 
-		keep, varType, _ := s.fieldToProperty(&field, b, varSequence, variablePaths, path)
-		if !keep {
-			continue
-		}
+		varType, _ := s.fieldToProperty(field.name, field.typ, b, varSequence, variablePaths, path)
+		quoted := s.options.Compat && field.quoted
 
 		if varType == propertyVariable {
 
-			format, err := s.getFormatSymbol(field.Type.Kind())
-			if err != nil {
-				return err
+			format := strStringVar
+
+			if wholeValues && !s.options.Compat && (kind == reflect.Map || kind == reflect.Array || kind == reflect.Slice) && !encoded && !s.isBytes(field.typ) {
+				// rendered like any JSON value, so the nil ones are written as null
+				(*varSequence)[len(*varSequence)-1].kind = reflect.Interface
+			} else {
+				var err error
+				format, err = s.getFormatSymbol(field.typ)
+				if err != nil {
+					return err
+				}
 			}
 
 			b.WriteString(format)
 
+			(*varSequence)[len(*varSequence)-1].quoted = quoted
+
+			err = s.setDefaultValue(&fv, varSequence)
+			if err != nil {
+				return err
+			}
+
 		} else {
 
-			vf := v.Field(i)
-			value, err := s.getValueFromField(&field, &vf)
+			var value string
+			if quoted {
+				value, err = s.renderQuoted(&fv)
+			} else {
+				value, err = s.getValueFromField(nil, &fv)
+			}
+
 			if err != nil {
 				return err
 			}
 
-			b.WriteString(value)
+			b.WriteString(s.escapeFormat(value))
 		}
+	}
 
-		if i < numFields-1 {
-			b.WriteString(strComma)
+	return nil
+}
+
+// setDefaultValue - renders the value as the default of the last mapped variable
+func (s *Serializer) setDefaultValue(value *reflect.Value, varSequence *[]variable) error {
+
+	var rendered interface{}
+	var err error
+
+	if (*varSequence)[len(*varSequence)-1].quoted {
+		var quoted string
+		quoted, err = s.renderQuoted(value)
+		rendered = rawValue(quoted)
+	} else if (*varSequence)[len(*varSequence)-1].kind == reflect.Interface {
+		rendered, err = s.renderAny(value)
+	} else {
+		rendered, err = s.renderVariable(value)
+	}
+
+	if err != nil {
+		// custom encoders may reject the mapped value (like a zero value), the variable is left without default
+		if s.hasEncoder(value.Type()) {
+			return nil
 		}
+		return err
 	}
 
+	(*varSequence)[len(*varSequence)-1].defaultValue = rendered
+
 	return nil
 }
 
-// getFormatSymbol - returns the format from the struct field
-func (s *Serializer) getFormatSymbol(k reflect.Kind) (string, error) {
+// getFormatSymbol - returns the format from the struct field type
+func (s *Serializer) getFormatSymbol(t reflect.Type) (string, error) {
+
+	if s.hasEncoder(t) || s.isBytes(t) {
+		return strStringVar, nil
+	}
+
+	k := t.Kind()
 
 	switch k {
 	case reflect.String:
@@ -282,6 +450,13 @@ func (s *Serializer) getFormatSymbol(k reflect.Kind) (string, error) {
 		return strFloatVar, nil
 	case reflect.Bool:
 		return strBooleanVar, nil
+	case reflect.Interface, reflect.Ptr:
+		return strStringVar, nil
+	case reflect.Map, reflect.Array, reflect.Slice, reflect.Struct:
+		if s.options.Compat {
+			return strStringVar, nil
+		}
+		return serializer.Empty, fmt.Errorf("type not mapped: %d", k)
 	default:
 		return serializer.Empty, fmt.Errorf("type not mapped: %d", k)
 	}
@@ -290,6 +465,14 @@ func (s *Serializer) getFormatSymbol(k reflect.Kind) (string, error) {
 // getValueFromField - returns the value from the struct field
 func (s *Serializer) getValueFromField(field *reflect.StructField, value *reflect.Value) (string, error) {
 
+	if encoded, ok, err := s.encodeCustom(value); ok {
+		return encoded, err
+	}
+
+	if encoded, ok, err := s.encodeBytes(value); ok {
+		return encoded, err
+	}
+
 	var kind reflect.Kind
 	if field == nil {
 		kind = value.Type().Kind()
@@ -304,27 +487,46 @@ func (s *Serializer) getValueFromField(field *reflect.StructField, value *reflec
 		b.Grow(len(str) + 2 + (strings.Count(str, strDoubleQuote) * 2))
 		s.writeStringValue(str, &b)
 		return b.String(), nil
-	case reflect.Uint, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uint8, reflect.Uintptr, reflect.Int, reflect.Int16, reflect.Int32, reflect.Int64, reflect.Int8:
+	case reflect.Uint, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uint8, reflect.Uintptr:
+		if s.options.Canonical {
+			return s.formatCanonicalUnsigned(value.Uint())
+		}
+		return strconv.FormatUint(value.Uint(), 10), nil
+	case reflect.Int, reflect.Int16, reflect.Int32, reflect.Int64, reflect.Int8:
+		if s.options.Canonical {
+			return s.formatCanonicalInteger(value.Int())
+		}
 		return strconv.FormatInt(value.Int(), 10), nil
 	case reflect.Float32, reflect.Float64:
-		return strconv.FormatFloat(value.Float(), serializer.ByteFloatFormat, -1, 64), nil
+		return s.formatFloat(value)
 	case reflect.Bool:
 		return strconv.FormatBool(value.Bool()), nil
 	case reflect.Interface:
-		iface := value.Interface()
-		if serializer.InterfaceHasZeroValue(iface) {
+		if value.IsNil() {
 			return serializer.Null, nil
 		}
-		internalValue := reflect.ValueOf(iface)
+		internalValue := value.Elem()
 		return s.getValueFromField(nil, &internalValue)
+	case reflect.Map, reflect.Array, reflect.Slice, reflect.Struct, reflect.Ptr:
+		return s.renderTree(value, 0)
 	default:
 		return serializer.Empty, fmt.Errorf("kind not mapped: %s", kind.String())
 	}
 }
 
-// writeStringValue - writes a string in JSON format
+// writeStringValue - writes a string in JSON format, escaping the quotes, the bars and the control characters
 func (s *Serializer) writeStringValue(value string, b *strings.Builder) {
 
+	if s.options.Canonical {
+		s.writeCanonicalString(value, b)
+		return
+	}
+
+	if s.options.Compat {
+		s.writeCompatString(value, b)
+		return
+	}
+
 	b.WriteByte(byteValueDoubleQuote)
 
 	for _, c := range []byte(value) {
@@ -332,6 +534,16 @@ func (s *Serializer) writeStringValue(value string, b *strings.Builder) {
 			b.WriteString(jsonEscapedDoubleQuote)
 		} else if c == byteValueEscapeBar {
 			b.WriteString(jsonEscapedEscapeBar)
+		} else if c == '\n' {
+			b.WriteString(strEscapedNewLine)
+		} else if c == '\r' {
+			b.WriteString(strEscapedReturn)
+		} else if c == '\t' {
+			b.WriteString(strEscapedTab)
+		} else if c < 0x20 || (s.options.HTMLEscape && (c == '<' || c == '>' || c == '&')) {
+			b.WriteString(strUnicodeEscape)
+			b.WriteByte(hexDigits[c>>4])
+			b.WriteByte(hexDigits[c&0xF])
 		} else {
 			b.WriteByte(c)
 		}
@@ -340,34 +552,34 @@ func (s *Serializer) writeStringValue(value string, b *strings.Builder) {
 	b.WriteByte(byteValueDoubleQuote)
 }
 
-// writePropertyString - writes a string in JSON format
+// writePropertyString - writes a property name in JSON format (escaping the verb symbol, it's written in the template)
 func (s *Serializer) writePropertyString(name string, b *strings.Builder) {
 
-	s.writeStringValue(name, b)
+	if strings.Contains(name, strPercent) {
+		var property strings.Builder
+		s.writeStringValue(name, &property)
+		b.WriteString(s.escapeFormat(property.String()))
+	} else {
+		s.writeStringValue(name, b)
+	}
+
 	b.WriteString(strColon)
 }
 
-// fieldToProperty - try to write a property, returns if it's a json property, the type and the current path
-func (s *Serializer) fieldToProperty(field *reflect.StructField, b *strings.Builder, varSequence *[]string, variablePaths map[string]struct{}, path string) (bool, variableType, string) {
+// fieldToProperty - writes the property name, returns the type and the current path
+func (s *Serializer) fieldToProperty(name string, t reflect.Type, b *strings.Builder, varSequence *[]variable, variablePaths map[string]struct{}, path string) (variableType, string) {
 
-	tag, ok := field.Tag.Lookup(strJSON)
-	if !ok {
-		return false, normalValue, path
-	}
-
-	tagValues := strings.Split(tag, strComma)
-
-	s.writePropertyString(tagValues[0], b)
+	s.writePropertyString(name, b)
 
-	propertyPath := s.buildPath(path, tagValues[0])
+	propertyPath := s.buildPath(path, name)
 	varType := normalValue
 
 	if _, ok := variablePaths[propertyPath]; ok {
 		varType = propertyVariable
-		*varSequence = append(*varSequence, propertyPath)
+		*varSequence = append(*varSequence, variable{path: propertyPath, kind: s.variableKind(t)})
 	}
 
-	return true, varType, propertyPath
+	return varType, propertyPath
 }
 
 // buildPath - builds a new path
@@ -383,3 +595,32 @@ func (s *Serializer) buildPath(path, new string) string {
 
 	return temp.String()
 }
+
+// splitFormat - splits the format in the constant fragments around each variable
+func (s *Serializer) splitFormat(format string) []string {
+
+	fragments := []string{}
+
+	var b strings.Builder
+	b.Grow(len(format))
+
+	for i := 0; i < len(format); i++ {
+
+		if format[i] != bytePercent || i == len(format)-1 {
+			b.WriteByte(format[i])
+			continue
+		}
+
+		i++
+
+		if format[i] == bytePercent {
+			b.WriteByte(bytePercent)
+			continue
+		}
+
+		fragments = append(fragments, b.String())
+		b.Reset()
+	}
+
+	return append(fragments, b.String())
+}
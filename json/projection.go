@@ -0,0 +1,267 @@
+package json
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/uol/serializer/serializer"
+)
+
+/**
+* Has the projections from the JSON serializer, subsets of the properties of a mapping.
+* @author rnojiri
+**/
+
+// AddProjection - adds a new JSON mapping with only the selected properties (and their sub-properties) of the named
+// mapping, the template is precompiled from the mapping segments and the variables of the other properties are ignored
+func (s *Serializer) AddProjection(name, projectionName string, paths ...string) error {
+
+	m, ok := s.getMapping(name)
+	if !ok {
+		return fmt.Errorf("no json mapping with name \"%s\"", name)
+	}
+
+	p, err := s.project(m, paths)
+	if err != nil {
+		return err
+	}
+
+	s.mappingLock.Lock()
+	s.mapping[projectionName] = p
+	s.mappingLock.Unlock()
+
+	return nil
+}
+
+// SerializeProjected - serializes only the selected properties of a mapped JSON, the projection is compiled on each
+// call (use AddProjection for the ones used frequently)
+func (s *Serializer) SerializeProjected(name string, paths []string, parameters ...interface{}) (result string, err error) {
+
+	defer s.options.Recover(&err)
+
+	return s.serializeProjected(name, paths, false, parameters)
+}
+
+// serializeProjected - serializes only the selected properties of a mapped JSON, masking the redacted properties if requested
+func (s *Serializer) serializeProjected(name string, paths []string, redacted bool, parameters []interface{}) (string, error) {
+
+	m, ok := s.getMapping(name)
+	if !ok {
+		return serializer.Empty, fmt.Errorf("no json mapping with name \"%s\"", name)
+	}
+
+	p, err := s.project(m, paths)
+	if err != nil {
+		return serializer.Empty, err
+	}
+
+	p = s.view(p, redacted)
+
+	params, supplied, err := s.buildParameters(p, parameters)
+	if err != nil {
+		return serializer.Empty, err
+	}
+
+	return s.execute(p, params, supplied)
+}
+
+// project - compiles a mapping with the selected properties, writing only the template segments of these properties
+func (s *Serializer) project(m *mappedJSON, paths []string) (*mappedJSON, error) {
+
+	if m.dynamic {
+		return nil, fmt.Errorf("dynamic mappings can't be projected")
+	}
+
+	if len(paths) == 0 {
+		return nil, fmt.Errorf("no properties to project")
+	}
+
+	scanner := &sectionScanner{
+		s:         s,
+		format:    m.format,
+		variables: m.variables,
+		all:       true,
+	}
+
+	err := scanner.scan()
+	if err != nil {
+		return nil, err
+	}
+
+	present := make([]bool, len(scanner.sections))
+	found := map[string]struct{}{}
+
+	for i, sec := range scanner.sections {
+		for _, path := range paths {
+			if s.isProjected(sec.path, path) {
+				present[i] = true
+				if sec.path == path {
+					found[path] = struct{}{}
+				}
+			}
+		}
+	}
+
+	for _, path := range paths {
+		if _, ok := found[path]; !ok {
+			return nil, fmt.Errorf(`projected property "%s" does not exist`, path)
+		}
+	}
+
+	segments := scanner.segments()
+	s.removeUnsuppliable(m, scanner.sections, segments, present)
+
+	var b strings.Builder
+	b.Grow(len(m.format))
+
+	variables := []variable{}
+	variableMap := map[string]int{}
+	variablePaths := map[string]struct{}{}
+	numRequired := 0
+
+	for _, seg := range segments {
+
+		if !s.isSegmentPresent(&seg, present) {
+			continue
+		}
+
+		b.WriteString(seg.format)
+
+		for i := seg.first; i < seg.first+seg.count; i++ {
+
+			v := m.variables[i]
+			variableMap[v.path] = len(variables)
+			variablePaths[v.path] = struct{}{}
+			variables = append(variables, v)
+
+			if v.required {
+				numRequired++
+			}
+		}
+	}
+
+	ignored := map[string]struct{}{}
+	for _, v := range m.variables {
+		if _, ok := variableMap[v.path]; !ok {
+			ignored[v.path] = struct{}{}
+		}
+	}
+
+	p := &mappedJSON{
+		format:       b.String(),
+		formatSize:   b.Len(),
+		numVariables: len(variables),
+		numRequired:  numRequired,
+		variableMap:  variableMap,
+		variables:    variables,
+		fragments:    s.splitFormat(b.String()),
+		item:         m.item,
+		paths:        variablePaths,
+		projected:    paths,
+		ignored:      ignored,
+		size:         serializer.NewAdaptiveSize(b.Len()),
+	}
+
+	err = s.deriveSections(m, p)
+	if err != nil {
+		return nil, err
+	}
+
+	if m.redacted != nil {
+		p.redacted, err = s.project(m.redacted, paths)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return p, nil
+}
+
+// removeUnsuppliable - removes from the projection the optional sections of the mapping without projected variables,
+// they could never be written
+func (s *Serializer) removeUnsuppliable(m *mappedJSON, sections []section, segments []segment, present []bool) {
+
+	if len(m.sections) == 0 {
+		return
+	}
+
+	projected := make([]bool, m.numVariables)
+
+	for _, seg := range segments {
+		if s.isSegmentPresent(&seg, present) {
+			for i := seg.first; i < seg.first+seg.count; i++ {
+				projected[i] = true
+			}
+		}
+	}
+
+	for _, optional := range m.sections {
+
+		suppliable := false
+		for i := optional.firstVar; i < optional.lastVar; i++ {
+			suppliable = suppliable || projected[i]
+		}
+
+		if suppliable {
+			continue
+		}
+
+		for i, sec := range sections {
+			if sec.path == optional.path {
+				present[i] = false
+			}
+		}
+	}
+}
+
+// isProjected - checks if the property is the projected one, one of its parents or one of its sub-properties
+func (s *Serializer) isProjected(property, projected string) bool {
+
+	return property == projected || s.isSubPath(property, projected) || s.isSubPath(projected, property)
+}
+
+// isSubPath - checks if the path is inside the parent path ("a.b" and "a[0]" are inside "a")
+func (s *Serializer) isSubPath(path, parent string) bool {
+
+	return strings.HasPrefix(path, parent+strDot) || strings.HasPrefix(path, parent+strSquareBracketLeft)
+}
+
+// hasProjectedVariable - checks if one of the section variables is in the projection
+func (s *Serializer) hasProjectedVariable(m, p *mappedJSON, sec *section) bool {
+
+	for i := sec.firstVar; i < sec.lastVar; i++ {
+		if _, ok := p.variableMap[m.variables[i].path]; ok {
+			return true
+		}
+	}
+
+	return false
+}
+
+// deriveSections - compiles the optional sections and the indented template of a mapping derived from another one
+// (projections and redactions) like the original ones
+func (s *Serializer) deriveSections(m, p *mappedJSON) error {
+
+	optional := map[string]struct{}{}
+
+	for _, sec := range m.sections {
+		if s.hasProjectedVariable(m, p, &sec) {
+			optional[sec.path] = struct{}{}
+		}
+	}
+
+	if len(optional) > 0 {
+
+		var err error
+		p.sections, p.segments, err = s.mapSections(p.format, p.variables, optional, serializer.Empty, serializer.Empty)
+		if err != nil {
+			return err
+		}
+	}
+
+	if m.indented != nil {
+		return s.mapIndented(p, m.indented.prefix, m.indented.indent, optional)
+	}
+
+	return nil
+}
@@ -0,0 +1,80 @@
+package json
+
+import (
+	"encoding/base64"
+	stdjson "encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/uol/serializer/serializer"
+)
+
+/**
+* Has the byte slices and raw JSON rendering from the JSON serializer.
+* @author rnojiri
+**/
+
+// Raw - a pre serialized JSON fragment, inserted as is (validated only with the WithRawValidation option)
+type Raw = stdjson.RawMessage
+
+var rawType = reflect.TypeOf(Raw{})
+
+// isRaw - checks if the type is a raw JSON fragment
+func (s *Serializer) isRaw(t reflect.Type) bool {
+
+	return t == rawType
+}
+
+// isBytes - checks if the type is a byte slice, rendered as a base64 string like encoding/json does
+func (s *Serializer) isBytes(t reflect.Type) bool {
+
+	return t.Kind() == reflect.Slice && t.Elem().Kind() == reflect.Uint8
+}
+
+// encodeBytes - renders a raw fragment or a byte slice as base64, returns false if the value is none of them
+func (s *Serializer) encodeBytes(value *reflect.Value) (string, bool, error) {
+
+	t := value.Type()
+
+	if s.isRaw(t) {
+
+		raw := value.Bytes()
+		if len(raw) == 0 {
+			return serializer.Null, true, nil
+		}
+
+		if s.options.Canonical {
+			canonical, err := s.canonicalize(string(raw))
+			return canonical, true, err
+		}
+
+		if s.options.RawValidation && !stdjson.Valid(raw) {
+			return serializer.Empty, true, fmt.Errorf("invalid raw json: %s", string(raw))
+		}
+
+		return string(raw), true, nil
+	}
+
+	if !s.isBytes(t) {
+		return serializer.Empty, false, nil
+	}
+
+	if value.IsNil() {
+		return serializer.Null, true, nil
+	}
+
+	var b strings.Builder
+	b.Grow(base64.StdEncoding.EncodedLen(value.Len()) + 2)
+	b.WriteString(strDoubleQuote)
+	b.WriteString(base64.StdEncoding.EncodeToString(value.Bytes()))
+	b.WriteString(strDoubleQuote)
+
+	return b.String(), true, nil
+}
+
+// escapeFormat - escapes the verb symbol of a constant written in the template
+func (s *Serializer) escapeFormat(constant string) string {
+
+	return strings.ReplaceAll(constant, strPercent, strEscapedPercent)
+}
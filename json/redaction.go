@@ -0,0 +1,257 @@
+package json
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/uol/serializer/serializer"
+)
+
+/**
+* Has the sensitive properties redaction from the JSON serializer.
+* @author rnojiri
+**/
+
+const (
+	tagRedact     string = "redact"
+	strRedactMask string = "***"
+)
+
+// RedactFunc - returns the mask of a redacted value (received in its JSON form), the mask is written as a JSON string
+type RedactFunc func(value string) string
+
+// MaskValue - masks any value as "***"
+func MaskValue(value string) string {
+
+	return strRedactMask
+}
+
+// HashValue - masks the value as the hexadecimal SHA-256 of its JSON form, equal values have equal masks
+func HashValue(value string) string {
+
+	sum := sha256.Sum256([]byte(value))
+
+	return hex.EncodeToString(sum[:])
+}
+
+// RedactedSerializer - a view of the serializer masking the redacted properties, it shares the mappings of the serializer
+type RedactedSerializer struct {
+	serializer *Serializer
+}
+
+// Redacted - returns a view of the serializer masking the redacted properties (the fields tagged with the redact
+// option and the paths given by the Redact option)
+func (s *Serializer) Redacted() *RedactedSerializer {
+
+	return &RedactedSerializer{
+		serializer: s,
+	}
+}
+
+// Serialize - serializes a mapped JSON with the redacted properties masked
+func (r *RedactedSerializer) Serialize(name string, parameters ...interface{}) (result string, err error) {
+
+	defer r.serializer.options.Recover(&err)
+
+	return r.serializer.serialize(name, true, parameters)
+}
+
+// SerializeIndent - serializes a mapped JSON using the indented template with the redacted properties masked
+func (r *RedactedSerializer) SerializeIndent(name string, parameters ...interface{}) (result string, err error) {
+
+	defer r.serializer.options.Recover(&err)
+
+	return r.serializer.serializeIndent(name, true, parameters)
+}
+
+// SerializeArray - serializes an array of jsons with the redacted properties masked
+func (r *RedactedSerializer) SerializeArray(items ...*ArrayItem) (result string, err error) {
+
+	defer r.serializer.options.Recover(&err)
+
+	return r.serializer.serializeItems(items, true)
+}
+
+// SerializeProjected - serializes only the selected properties of a mapped JSON with the redacted properties masked
+func (r *RedactedSerializer) SerializeProjected(name string, paths []string, parameters ...interface{}) (result string, err error) {
+
+	defer r.serializer.options.Recover(&err)
+
+	return r.serializer.serializeProjected(name, paths, true, parameters)
+}
+
+// Marshal - serializes a struct (or a pointer to it) with the fields tagged with the redact option masked
+func (r *RedactedSerializer) Marshal(item interface{}) (result string, err error) {
+
+	defer r.serializer.options.Recover(&err)
+
+	return r.serializer.marshal(item, true)
+}
+
+// view - returns the redacted version of the mapping if it must be masked
+func (s *Serializer) view(m *mappedJSON, redacted bool) *mappedJSON {
+
+	if (redacted || s.options.AlwaysRedact) && m.redacted != nil {
+		return m.redacted
+	}
+
+	return m
+}
+
+// maskValue - renders the mask of a value in its JSON form
+func (s *Serializer) maskValue(value string) string {
+
+	var b strings.Builder
+	s.writeStringValue(s.options.Redaction(value), &b)
+
+	return b.String()
+}
+
+// maskParameters - replaces the parameters of the redacted variables by their masks
+func (s *Serializer) maskParameters(m *mappedJSON, params []interface{}) {
+
+	for i := 0; i < m.numVariables; i++ {
+		if m.variables[i].redactVerb != serializer.Empty {
+			params[i] = rawValue(s.maskValue(fmt.Sprintf(m.variables[i].redactVerb, params[i])))
+		}
+	}
+}
+
+// redactedTagPaths - adds the paths of the struct fields tagged with the redact option
+func (s *Serializer) redactedTagPaths(t reflect.Type, paths map[string]struct{}, path string) {
+
+	for _, field := range s.typeFields(t) {
+
+		currentPath := s.buildPath(path, field.name)
+
+		if field.redact {
+			paths[currentPath] = struct{}{}
+			continue
+		}
+
+		if field.typ.Kind() == reflect.Struct && !s.hasEncoder(field.typ) {
+			s.redactedTagPaths(field.typ, paths, currentPath)
+		}
+	}
+}
+
+// hasRedactedFields - checks if the struct type has fields tagged with the redact option
+func (s *Serializer) hasRedactedFields(t reflect.Type) bool {
+
+	paths := map[string]struct{}{}
+	s.redactedTagPaths(t, paths, serializer.Empty)
+
+	return len(paths) > 0
+}
+
+// mapRedacted - compiles the redacted version of the mapping from the tagged fields and the given paths (which must exist)
+func (s *Serializer) mapRedacted(m *mappedJSON, paths []string) error {
+
+	redactPaths := map[string]struct{}{}
+	for _, path := range paths {
+		redactPaths[path] = struct{}{}
+	}
+
+	if t := reflect.TypeOf(m.item); t != nil && t.Kind() == reflect.Struct {
+		s.redactedTagPaths(t, redactPaths, serializer.Empty)
+	}
+
+	if len(redactPaths) == 0 {
+		return nil
+	}
+
+	var err error
+	m.redacted, err = s.redactMapping(m, redactPaths, paths)
+
+	return err
+}
+
+// redactMapping - compiles a copy of the mapping with the redacted constants masked in the template and the redacted
+// variables flagged to be masked on serialization
+func (s *Serializer) redactMapping(m *mappedJSON, redactPaths map[string]struct{}, mustExist []string) (*mappedJSON, error) {
+
+	scanner := &sectionScanner{
+		s:         s,
+		format:    m.format,
+		variables: m.variables,
+		all:       true,
+	}
+
+	err := scanner.scan()
+	if err != nil {
+		return nil, err
+	}
+
+	variables := make([]variable, len(m.variables))
+	copy(variables, m.variables)
+
+	var b strings.Builder
+	b.Grow(len(m.format))
+
+	found := map[string]struct{}{}
+	last := 0
+
+	for _, sec := range scanner.sections {
+
+		if _, ok := redactPaths[sec.path]; !ok || sec.start < last {
+			continue
+		}
+
+		found[sec.path] = struct{}{}
+
+		value := m.format[sec.valueStart:sec.end]
+
+		b.WriteString(m.format[last:sec.valueStart])
+
+		switch numVerbs := scanner.countVerbs(value); {
+		case numVerbs == 0:
+			b.WriteString(s.escapeFormat(s.maskValue(strings.ReplaceAll(value, strEscapedPercent, strPercent))))
+		case numVerbs == 1 && s.isWholeVerb(value):
+			b.WriteString(strStringVar)
+			variables[sec.lastVar-1].redactVerb = value
+		default:
+			return nil, fmt.Errorf(`the redacted property "%s" must be a constant or a whole variable`, sec.path)
+		}
+
+		last = sec.end
+	}
+
+	b.WriteString(m.format[last:])
+
+	for _, path := range mustExist {
+		if _, ok := found[path]; !ok {
+			return nil, fmt.Errorf(`redacted property "%s" does not exist`, path)
+		}
+	}
+
+	r := &mappedJSON{
+		format:       b.String(),
+		formatSize:   b.Len(),
+		numVariables: m.numVariables,
+		numRequired:  m.numRequired,
+		variableMap:  m.variableMap,
+		variables:    variables,
+		fragments:    s.splitFormat(b.String()),
+		item:         m.item,
+		paths:        m.paths,
+		projected:    m.projected,
+		ignored:      m.ignored,
+		size:         serializer.NewAdaptiveSize(b.Len()),
+	}
+
+	err = s.deriveSections(m, r)
+	if err != nil {
+		return nil, err
+	}
+
+	return r, nil
+}
+
+// isWholeVerb - checks if the property value is a single variable verb
+func (s *Serializer) isWholeVerb(value string) bool {
+
+	return (len(value) == 2 && value[0] == bytePercent) || value == strFmtStringInBrackets || value == strFmtStringInSqBrackets
+}
@@ -0,0 +1,317 @@
+package json
+
+import (
+	stdjson "encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+)
+
+/**
+* Has all JSON Schema methods from the JSON serializer.
+* @author rnojiri
+**/
+
+const (
+	schemaDraft string = "https://json-schema.org/draft/2020-12/schema"
+)
+
+// schemaObject - a JSON schema object
+type schemaObject map[string]interface{}
+
+// Schema - returns a JSON Schema (draft 2020-12) describing the mapped JSON, constants are described as "const"
+func (s *Serializer) Schema(name string) ([]byte, error) {
+
+	m, ok := s.getMapping(name)
+	if !ok {
+		return nil, fmt.Errorf("no json mapping with name \"%s\"", name)
+	}
+
+	schema := schemaObject{
+		"$schema": schemaDraft,
+		"title":   name,
+	}
+
+	v := reflect.ValueOf(m.item)
+
+	err := s.structSchema(&v, m.paths, "", schema)
+	if err != nil {
+		return nil, err
+	}
+
+	if m.projected != nil {
+		s.projectSchema(schema, m.projected, "")
+	}
+
+	for _, sec := range m.sections {
+		s.optionalSchema(schema, sec.path)
+	}
+
+	return stdjson.Marshal(schema)
+}
+
+// structSchema - fills the object schema with the struct properties
+func (s *Serializer) structSchema(v *reflect.Value, variablePaths map[string]struct{}, path string, schema schemaObject) error {
+
+	if _, ok := schema["properties"]; !ok {
+		schema["type"] = "object"
+		schema["properties"] = schemaObject{}
+		schema["required"] = []string{}
+		schema["additionalProperties"] = false
+	}
+
+	properties := schema["properties"].(schemaObject)
+
+	for _, field := range s.typeFields(v.Type()) {
+
+		fv, ok := s.fieldByIndex(*v, field.index)
+		if !ok {
+			continue
+		}
+
+		currentPath := s.buildPath(path, field.name)
+
+		var propertySchema schemaObject
+
+		if field.typ.Kind() == reflect.Struct && !s.hasEncoder(field.typ) {
+
+			propertySchema = schemaObject{}
+			err := s.structSchema(&fv, variablePaths, currentPath, propertySchema)
+			if err != nil {
+				return err
+			}
+
+		} else {
+
+			var err error
+			_, isVariable := variablePaths[currentPath]
+			propertySchema, err = s.valueSchema(&fv, variablePaths, currentPath, isVariable)
+			if err != nil {
+				return err
+			}
+		}
+
+		properties[field.name] = propertySchema
+		schema["required"] = append(schema["required"].([]string), field.name)
+	}
+
+	return nil
+}
+
+// projectSchema - removes the properties out of the projection from the object schema
+func (s *Serializer) projectSchema(schema schemaObject, projected []string, path string) {
+
+	properties, ok := schema["properties"].(schemaObject)
+	if !ok {
+		return
+	}
+
+	required := []string{}
+
+	for _, name := range schema["required"].([]string) {
+
+		propertyPath := s.buildPath(path, name)
+		kept, inside := false, false
+
+		for _, p := range projected {
+			kept = kept || s.isProjected(propertyPath, p)
+			inside = inside || propertyPath == p || s.isSubPath(propertyPath, p)
+		}
+
+		if !kept {
+			delete(properties, name)
+			continue
+		}
+
+		if child, isObject := properties[name].(schemaObject); isObject && !inside {
+			s.projectSchema(child, projected, propertyPath)
+		}
+
+		required = append(required, name)
+	}
+
+	schema["required"] = required
+}
+
+// optionalSchema - removes the optional property from the required ones of its parent object
+func (s *Serializer) optionalSchema(schema schemaObject, path string) {
+
+	parts := s.splitPath(path)
+	current := schema
+
+	for _, part := range parts[:len(parts)-1] {
+
+		var next schemaObject
+		var ok bool
+
+		if index, isIndex := s.arrayIndex(part); isIndex {
+			items, isArray := current["prefixItems"].([]schemaObject)
+			if !isArray || index >= len(items) {
+				return
+			}
+			next, ok = items[index], true
+		} else if properties, isObject := current["properties"].(schemaObject); isObject {
+			next, ok = properties[part].(schemaObject)
+		}
+
+		if !ok {
+			return
+		}
+
+		current = next
+	}
+
+	name := parts[len(parts)-1]
+	required, _ := current["required"].([]string)
+	filtered := make([]string, 0, len(required))
+
+	for _, property := range required {
+		if property != name {
+			filtered = append(filtered, property)
+		}
+	}
+
+	current["required"] = filtered
+}
+
+// valueSchema - returns the schema of a value, variables are described by their types and constants by their values
+func (s *Serializer) valueSchema(v *reflect.Value, variablePaths map[string]struct{}, path string, isVariable bool) (schemaObject, error) {
+
+	kind := v.Kind()
+	if s.hasEncoder(v.Type()) || s.isRaw(v.Type()) {
+		kind = reflect.Interface
+	} else if s.isBytes(v.Type()) {
+		if isVariable {
+			return schemaObject{"type": "string", "contentEncoding": "base64"}, nil
+		}
+		kind = reflect.Interface
+	}
+
+	if isVariable {
+
+		switch kind {
+		case reflect.Map:
+			return schemaObject{
+				"type":                 "object",
+				"additionalProperties": s.kindSchema(v.Type().Elem().Kind()),
+			}, nil
+		case reflect.Array, reflect.Slice:
+			return schemaObject{
+				"type":  "array",
+				"items": s.kindSchema(v.Type().Elem().Kind()),
+			}, nil
+		default:
+			return s.kindSchema(kind), nil
+		}
+	}
+
+	switch kind {
+	case reflect.Map:
+
+		properties := schemaObject{}
+		required := []string{}
+		additional := []schemaObject{}
+
+		keys, err := s.mapKeys(v)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, k := range keys {
+
+			key := k.name
+			val := v.MapIndex(k.value)
+			keyPath := s.buildPath(path, key)
+			_, isVariable := variablePaths[keyPath]
+
+			keySchema, err := s.valueSchema(&val, variablePaths, keyPath, isVariable)
+			if err != nil {
+				return nil, err
+			}
+
+			// the key variables names are unknown, their values are described as additional properties
+			if _, isKeyVariable := variablePaths[s.buildPath(path, strKeyVariable+key)]; isKeyVariable {
+				additional = append(additional, keySchema)
+				continue
+			}
+
+			properties[key] = keySchema
+			required = append(required, key)
+		}
+
+		object := schemaObject{
+			"type":                 "object",
+			"properties":           properties,
+			"required":             required,
+			"additionalProperties": false,
+		}
+
+		if len(additional) == 1 {
+			object["additionalProperties"] = additional[0]
+		} else if len(additional) > 1 {
+			object["additionalProperties"] = schemaObject{"anyOf": additional}
+		}
+
+		if len(additional) > 0 {
+			object["minProperties"] = len(keys)
+		}
+
+		return object, nil
+
+	case reflect.Array, reflect.Slice:
+
+		items := make([]schemaObject, v.Len())
+
+		for i := 0; i < v.Len(); i++ {
+
+			val := v.Index(i)
+			indexPath := path + strSquareBracketLeft + strconv.Itoa(i) + strSquareBracketRight
+			_, isVariable := variablePaths[indexPath]
+
+			var err error
+			items[i], err = s.valueSchema(&val, variablePaths, indexPath, isVariable)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		return schemaObject{
+			"type":        "array",
+			"prefixItems": items,
+			"items":       false,
+			"minItems":    len(items),
+		}, nil
+
+	default:
+
+		constant, err := s.getValueFromField(nil, v)
+		if err != nil {
+			return nil, err
+		}
+
+		return schemaObject{
+			"const": stdjson.RawMessage(constant),
+		}, nil
+	}
+}
+
+// kindSchema - returns the type constraint of a kind
+func (s *Serializer) kindSchema(kind reflect.Kind) schemaObject {
+
+	switch kind {
+	case reflect.String:
+		return schemaObject{"type": "string"}
+	case reflect.Uint, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uint8, reflect.Uintptr, reflect.Int, reflect.Int16, reflect.Int32, reflect.Int64, reflect.Int8:
+		return schemaObject{"type": "integer"}
+	case reflect.Float32, reflect.Float64:
+		return schemaObject{"type": "number"}
+	case reflect.Bool:
+		return schemaObject{"type": "boolean"}
+	case reflect.Map:
+		return schemaObject{"type": "object"}
+	case reflect.Array, reflect.Slice:
+		return schemaObject{"type": "array"}
+	default:
+		return schemaObject{}
+	}
+}
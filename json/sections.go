@@ -0,0 +1,488 @@
+package json
+
+import (
+	stdjson "encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/uol/serializer/serializer"
+)
+
+/**
+* Has the optional sections from the JSON serializer, properties omitted when their variables are not supplied.
+* @author rnojiri
+**/
+
+// absence - the type of the Absent flag
+type absence struct{}
+
+// Absent - flags a variable as not supplied on serialization, its optional section is omitted if no other variable is supplied
+var Absent = absence{}
+
+// section - an optional property of the template, the offsets of the property and its variables range
+type section struct {
+	path       string
+	start      int
+	valueStart int
+	end        int
+	firstVar   int
+	lastVar    int
+}
+
+// conditionalRange - a template range written only if the required sections are present, and one of anyOf if given
+type conditionalRange struct {
+	start    int
+	end      int
+	required []int
+	anyOf    []int
+}
+
+// segment - a precompiled template fragment with its variables range and the sections it depends on
+type segment struct {
+	format   string
+	first    int
+	count    int
+	required []int
+	anyOf    [][]int
+}
+
+// sectionFrame - an object or array being scanned
+type sectionFrame struct {
+	object          bool
+	path            string
+	index           int
+	expectKey       bool
+	comma           int
+	open            bool
+	property        int
+	propertyPath    string
+	mandatoryBefore bool
+	optionalBefore  []int
+}
+
+// sectionScanner - scans a template to find the optional properties (or all of them) and the commas depending on them
+type sectionScanner struct {
+	s         *Serializer
+	format    string
+	prefix    string
+	indent    string
+	variables []variable
+	optional  map[string]struct{}
+	all       bool
+	sections  []section
+	ranges    []conditionalRange
+	stack     []*sectionFrame
+	varIndex  int
+	lastEnd   int
+}
+
+// mapSections - finds the optional sections of the template (compact or indented) and splits it in segments
+func (s *Serializer) mapSections(format string, variables []variable, optional map[string]struct{}, prefix, indent string) ([]section, []segment, error) {
+
+	scanner := &sectionScanner{
+		s:         s,
+		format:    format,
+		prefix:    prefix,
+		indent:    indent,
+		variables: variables,
+		optional:  optional,
+	}
+
+	err := scanner.scan()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	found := map[string]struct{}{}
+
+	for _, sec := range scanner.sections {
+
+		if sec.firstVar == sec.lastVar {
+			return nil, nil, fmt.Errorf(`the optional property "%s" has no variables`, sec.path)
+		}
+
+		found[sec.path] = struct{}{}
+	}
+
+	for path := range optional {
+		if _, ok := found[path]; !ok {
+			return nil, nil, fmt.Errorf(`optional property "%s" does not exist`, path)
+		}
+	}
+
+	return scanner.sections, scanner.segments(), nil
+}
+
+// scan - walks the template tokens, the variable verbs are counted to know the variables of each section
+func (sc *sectionScanner) scan() error {
+
+	f := sc.format
+
+	for i := 0; i < len(f); {
+
+		if next, ok := sc.skipSpace(i); ok {
+			i = next
+			continue
+		}
+
+		c := f[i]
+		frame := sc.top()
+
+		switch c {
+		case byteValueDoubleQuote:
+
+			end, err := sc.s.scanValue([]byte(f), i)
+			if err != nil {
+				return err
+			}
+
+			if frame != nil && frame.object && frame.expectKey {
+				name, err := sc.propertyName(f[i:end])
+				if err != nil {
+					return err
+				}
+				sc.openProperty(frame, i, sc.s.buildPath(frame.path, name))
+			}
+
+			i = end
+
+		case bytePercent:
+
+			if i+1 < len(f) && f[i+1] == bytePercent {
+				i += 2
+				break
+			}
+
+			if frame != nil && frame.object && frame.expectKey && sc.varIndex < len(sc.variables) {
+				sc.openProperty(frame, i, sc.keyPropertyPath(sc.variables[sc.varIndex].path))
+			}
+
+			sc.varIndex++
+			i += 2
+
+		case '{', '[':
+
+			path := serializer.Empty
+			if frame != nil {
+				path = frame.valuePath()
+			}
+
+			sc.stack = append(sc.stack, &sectionFrame{
+				object:    c == '{',
+				path:      path,
+				expectKey: c == '{',
+				comma:     -1,
+				property:  -1,
+			})
+
+			i++
+
+		case '}', ']':
+
+			if frame == nil {
+				return fmt.Errorf("unexpected %c at offset %d", c, i)
+			}
+
+			sc.closeProperty(frame)
+			sc.stack = sc.stack[:len(sc.stack)-1]
+			i++
+
+		case ',':
+
+			if frame != nil && frame.object {
+				sc.closeProperty(frame)
+				frame.comma = i
+				frame.expectKey = true
+			} else if frame != nil {
+				frame.index++
+			}
+
+			i++
+			sc.lastEnd = i
+			continue
+
+		case ':':
+
+			if frame != nil {
+				frame.expectKey = false
+				if frame.property >= 0 {
+					sc.sections[frame.property].valueStart = i + 1
+				}
+			}
+
+			i++
+
+		default:
+
+			i++
+		}
+
+		sc.lastEnd = i
+	}
+
+	return nil
+}
+
+// skipSpace - skips the indentation whitespaces (new lines followed by the prefix and the indent copies)
+func (sc *sectionScanner) skipSpace(i int) (int, bool) {
+
+	f := sc.format
+
+	switch f[i] {
+	case ' ', '\t', '\r':
+		return i + 1, true
+	case '\n':
+		i++
+		if len(sc.prefix) > 0 && strings.HasPrefix(f[i:], sc.prefix) {
+			i += len(sc.prefix)
+		}
+		for len(sc.indent) > 0 && strings.HasPrefix(f[i:], sc.indent) {
+			i += len(sc.indent)
+		}
+		return i, true
+	default:
+		return i, false
+	}
+}
+
+// top - returns the innermost object or array being scanned
+func (sc *sectionScanner) top() *sectionFrame {
+
+	if len(sc.stack) == 0 {
+		return nil
+	}
+
+	return sc.stack[len(sc.stack)-1]
+}
+
+// valuePath - returns the path of the value being scanned in the frame
+func (f *sectionFrame) valuePath() string {
+
+	if f.object {
+		return f.propertyPath
+	}
+
+	return f.path + strSquareBracketLeft + fmt.Sprint(f.index) + strSquareBracketRight
+}
+
+// propertyName - decodes a property name written in the template
+func (sc *sectionScanner) propertyName(token string) (string, error) {
+
+	var name string
+
+	err := stdjson.Unmarshal([]byte(strings.ReplaceAll(token, strEscapedPercent, strPercent)), &name)
+	if err != nil {
+		return serializer.Empty, fmt.Errorf("invalid property name %s: %s", token, err.Error())
+	}
+
+	return name, nil
+}
+
+// keyPropertyPath - returns the property path of a key variable ("tags.@host" -> "tags.host")
+func (sc *sectionScanner) keyPropertyPath(path string) string {
+
+	if i := strings.LastIndex(path, strDot+strKeyVariable); i >= 0 {
+		return path[:i+1] + path[i+2:]
+	}
+
+	return strings.TrimPrefix(path, strKeyVariable)
+}
+
+// openProperty - starts a property, opening its section if optional and the conditional range of the comma before it
+func (sc *sectionScanner) openProperty(frame *sectionFrame, start int, path string) {
+
+	frame.open = true
+	frame.propertyPath = path
+	frame.expectKey = false
+	frame.property = -1
+
+	_, optional := sc.optional[path]
+	optional = optional || sc.all
+
+	if optional {
+		frame.property = len(sc.sections)
+		sc.sections = append(sc.sections, section{path: path, start: start, firstVar: sc.varIndex})
+	}
+
+	if frame.comma >= 0 {
+
+		comma := conditionalRange{start: frame.comma, end: start}
+
+		if optional {
+			comma.required = []int{frame.property}
+		}
+
+		// without a mandatory property before, the comma is written only if one of the optional ones was
+		if !frame.mandatoryBefore && len(frame.optionalBefore) > 0 {
+			comma.anyOf = append([]int{}, frame.optionalBefore...)
+		}
+
+		if comma.required != nil || comma.anyOf != nil {
+			sc.ranges = append(sc.ranges, comma)
+		}
+
+		frame.comma = -1
+	}
+}
+
+// closeProperty - ends the property being scanned in the object, closing its section if optional
+func (sc *sectionScanner) closeProperty(frame *sectionFrame) {
+
+	if !frame.object || !frame.open {
+		return
+	}
+
+	if frame.property >= 0 {
+
+		sec := &sc.sections[frame.property]
+		sec.end = sc.lastEnd
+		sec.lastVar = sc.varIndex
+
+		sc.ranges = append(sc.ranges, conditionalRange{start: sec.start, end: sec.end, required: []int{frame.property}})
+		frame.optionalBefore = append(frame.optionalBefore, frame.property)
+
+	} else {
+
+		frame.mandatoryBefore = true
+	}
+
+	frame.open = false
+	frame.property = -1
+}
+
+// segments - splits the template in the boundaries of the conditional ranges
+func (sc *sectionScanner) segments() []segment {
+
+	boundaries := []int{0, len(sc.format)}
+	for _, r := range sc.ranges {
+		boundaries = append(boundaries, r.start, r.end)
+	}
+
+	sort.Ints(boundaries)
+
+	segments := []segment{}
+	varIndex := 0
+
+	for i := 1; i < len(boundaries); i++ {
+
+		start, end := boundaries[i-1], boundaries[i]
+		if start == end {
+			continue
+		}
+
+		seg := segment{format: sc.format[start:end], first: varIndex}
+		seg.count = sc.countVerbs(seg.format)
+		varIndex += seg.count
+
+		for _, r := range sc.ranges {
+
+			if r.start > start || r.end < end {
+				continue
+			}
+
+			seg.required = append(seg.required, r.required...)
+			if r.anyOf != nil {
+				seg.anyOf = append(seg.anyOf, r.anyOf)
+			}
+		}
+
+		last := len(segments) - 1
+		if last >= 0 && fmt.Sprint(segments[last].required, segments[last].anyOf) == fmt.Sprint(seg.required, seg.anyOf) {
+			segments[last].format += seg.format
+			segments[last].count += seg.count
+			continue
+		}
+
+		segments = append(segments, seg)
+	}
+
+	return segments
+}
+
+// countVerbs - counts the variable verbs of a template fragment
+func (sc *sectionScanner) countVerbs(format string) int {
+
+	count := 0
+
+	for i := 0; i < len(format)-1; i++ {
+		if format[i] == bytePercent {
+			if format[i+1] != bytePercent {
+				count++
+			}
+			i++
+		}
+	}
+
+	return count
+}
+
+// presentSections - returns which sections have at least one supplied variable (nil if all are present)
+func (s *Serializer) presentSections(sections []section, supplied []bool) []bool {
+
+	if len(sections) == 0 {
+		return nil
+	}
+
+	present := make([]bool, len(sections))
+	all := true
+
+	for i, sec := range sections {
+
+		for v := sec.firstVar; v < sec.lastVar; v++ {
+			if supplied[v] {
+				present[i] = true
+				break
+			}
+		}
+
+		all = all && present[i]
+	}
+
+	if all {
+		return nil
+	}
+
+	return present
+}
+
+// writeSegments - writes the template segments of the present sections
+func (s *Serializer) writeSegments(w io.Writer, segments []segment, params []interface{}, present []bool) {
+
+	for _, seg := range segments {
+
+		if !s.isSegmentPresent(&seg, present) {
+			continue
+		}
+
+		fmt.Fprintf(w, seg.format, params[seg.first:seg.first+seg.count]...)
+	}
+}
+
+// isSegmentPresent - checks if all the segment required sections are present and one of each anyOf group
+func (s *Serializer) isSegmentPresent(seg *segment, present []bool) bool {
+
+	for _, id := range seg.required {
+		if !present[id] {
+			return false
+		}
+	}
+
+	for _, group := range seg.anyOf {
+
+		found := false
+		for _, id := range group {
+			if present[id] {
+				found = true
+				break
+			}
+		}
+
+		if !found {
+			return false
+		}
+	}
+
+	return true
+}
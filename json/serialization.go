@@ -50,9 +50,15 @@ func (s *Serializer) SerializeGenericArray(items ...interface{}) (string, error)
 }
 
 // SerializeArray - serializes an array of jsons
-func (s *Serializer) SerializeArray(items ...*ArrayItem) (string, error) {
+func (s *Serializer) SerializeArray(items ...*ArrayItem) (result string, err error) {
 
-	defer serializer.PanicHandler()
+	defer s.options.Recover(&err)
+
+	return s.serializeItems(items, false)
+}
+
+// serializeItems - serializes an array of jsons, masking the redacted properties if requested
+func (s *Serializer) serializeItems(items []*ArrayItem, redacted bool) (string, error) {
 
 	numItems := len(items)
 	if numItems == 0 {
@@ -64,15 +70,23 @@ func (s *Serializer) SerializeArray(items ...*ArrayItem) (string, error) {
 	jsons := make([]string, numItems)
 
 	for i := 0; i < numItems; i++ {
-		jsons[i], err = s.Serialize(items[i].Name, items[i].Parameters...)
+		jsons[i], err = s.serialize(items[i].Name, redacted, items[i].Parameters)
 		if err != nil {
 			return serializer.Empty, err
 		}
 		totalSize += len(jsons[i])
 	}
 
+	return s.joinArray(jsons, totalSize)
+}
+
+// joinArray - joins the serialized jsons as an array, checking the output size
+func (s *Serializer) joinArray(jsons []string, totalSize int) (string, error) {
+
+	numItems := len(jsons)
+
 	var b strings.Builder
-	b.Grow(totalSize + (numItems - 1) + 2)
+	b.Grow(totalSize + numItems + 2)
 
 	b.WriteString(strSquareBracketLeft)
 
@@ -87,124 +101,293 @@ func (s *Serializer) SerializeArray(items ...*ArrayItem) (string, error) {
 
 	b.WriteString(strSquareBracketRight)
 
+	err := s.options.CheckOutputSize(b.Len())
+	if err != nil {
+		return serializer.Empty, err
+	}
+
 	return b.String(), nil
 }
 
+// SerializeArrayLenient - serializes an array of jsons skipping the invalid items, a *serializer.BatchError is returned listing them
+// (an empty array is returned when no item is serialized)
+func (s *Serializer) SerializeArrayLenient(items ...*ArrayItem) (result string, err error) {
+
+	defer s.options.Recover(&err)
+
+	numItems := len(items)
+	batchErr := &serializer.BatchError{}
+	jsons := make([]string, 0, numItems)
+	totalSize := 0
+
+	for i := 0; i < numItems; i++ {
+
+		item, err := s.serializeItem(items[i], false)
+		if err != nil {
+			batchErr.Add(i, err)
+			continue
+		}
+
+		jsons = append(jsons, item)
+		totalSize += len(item)
+	}
+
+	result, err = s.joinArray(jsons, totalSize)
+	if err != nil {
+		return serializer.Empty, err
+	}
+
+	return result, batchErr.ErrorOrNil()
+}
+
 // Serialize - serializes a mapped JSON
-func (s *Serializer) Serialize(name string, parameters ...interface{}) (string, error) {
+func (s *Serializer) Serialize(name string, parameters ...interface{}) (result string, err error) {
+
+	defer s.options.Recover(&err)
+
+	return s.serialize(name, false, parameters)
+}
+
+// serializeItem - serializes an item of the methods serializing many items, returning the unexpected panics as errors
+func (s *Serializer) serializeItem(item *ArrayItem, redacted bool) (result string, err error) {
+
+	defer s.options.RecoverAsError(&err)
 
-	defer serializer.PanicHandler()
+	return s.serialize(item.Name, redacted, item.Parameters)
+}
+
+// serialize - serializes a mapped JSON, masking the redacted properties if requested
+func (s *Serializer) serialize(name string, redacted bool, parameters []interface{}) (string, error) {
 
-	m, ok := s.mapping[name]
+	m, ok := s.getMapping(name)
 	if !ok {
 		return serializer.Empty, fmt.Errorf("no json mapping with name \"%s\"", name)
 	}
 
-	if m.numVariables != len(parameters)/2 {
-		return serializer.Empty, fmt.Errorf("wrong number of variables")
+	m = s.view(m, redacted)
+
+	params, supplied, err := s.buildParameters(m, parameters)
+	if err != nil {
+		return serializer.Empty, err
+	}
+
+	return s.execute(m, params, supplied)
+}
+
+// execute - executes the mapping template using a pooled buffer, sized by the average output size of the mapping,
+// only the segments of the present sections are written when some optional section is not
+func (s *Serializer) execute(m *mappedJSON, params []interface{}, supplied []bool) (string, error) {
+
+	b := s.pool.Get(m.size.Get())
+	defer s.pool.Put(b)
+
+	if present := s.presentSections(m.sections, supplied); present != nil {
+		s.writeSegments(b, m.segments, params, present)
+	} else {
+		fmt.Fprintf(b, m.format, params...)
+	}
+
+	m.size.Observe(b.Len())
+
+	err := s.options.CheckOutputSize(b.Len())
+	if err != nil {
+		return serializer.Empty, err
+	}
+
+	return b.String(), nil
+}
+
+// PoolStats - returns the buffer pool statistics
+func (s *Serializer) PoolStats() serializer.PoolStats {
+
+	return s.pool.Stats()
+}
+
+// buildParameters - renders the template parameters, using the default values for the ones not supplied, returns which
+// variables were supplied when the mapping has required variables or optional sections
+func (s *Serializer) buildParameters(m *mappedJSON, parameters []interface{}) ([]interface{}, []bool, error) {
+
+	if len(parameters)%2 != 0 || len(parameters)/2 > m.numVariables+len(m.ignored) {
+		return nil, nil, fmt.Errorf("wrong number of variables")
 	}
 
 	params := make([]interface{}, m.numVariables)
+	for i := 0; i < m.numVariables; i++ {
+		params[i] = m.variables[i].defaultValue
+	}
+
+	var supplied []bool
+	if m.numRequired > 0 || len(m.sections) > 0 {
+		supplied = make([]bool, m.numVariables)
+	}
+
 	for i := 0; i < len(parameters); i += 2 {
 
 		if serializer.InterfaceHasZeroValue(parameters[i]) {
-			return serializer.Null, fmt.Errorf("variable name is null on index %d", i)
+			return nil, nil, fmt.Errorf("variable name is null on index %d", i)
 		}
 
 		varName, ok := parameters[i].(string)
 		if !ok {
-			return serializer.Empty, fmt.Errorf("error casting variable index %d to string", i)
-		}
-
-		genericValue := parameters[i+1]
-		if serializer.InterfaceHasZeroValue(genericValue) {
-			return serializer.Null, fmt.Errorf("value is null on index %d", i+1)
+			return nil, nil, fmt.Errorf("error casting variable index %d to string", i)
 		}
 
-		value := reflect.ValueOf(genericValue)
-		kind := value.Kind()
-
 		key, ok := m.variableMap[varName]
 		if !ok {
-			return serializer.Empty, fmt.Errorf(`variable "%s" does not exist`, varName)
+			// the variables of the properties out of a projection are accepted
+			if _, ignored := m.ignored[varName]; ignored {
+				continue
+			}
+			return nil, nil, fmt.Errorf(`variable "%s" does not exist`, varName)
 		}
 
-		if kind == reflect.Map {
+		genericValue := parameters[i+1]
+		if _, absent := genericValue.(absence); absent {
+			continue
+		}
 
-			strMap, err := s.serializeMap(&value)
-			if err != nil {
-				return serializer.Empty, err
+		if serializer.InterfaceHasZeroValue(genericValue) {
+
+			if m.variables[key].key && s.options.NullPolicy != serializer.NullSkip {
+				return nil, nil, fmt.Errorf(`the key variable "%s" can't be null`, varName)
 			}
 
-			params[key] = strMap
+			switch s.options.NullPolicy {
+			case serializer.NullWrite:
+				switch m.variables[key].kind {
+				case reflect.Map, reflect.Array, reflect.Slice:
+					params[key] = serializer.Empty
+				default:
+					params[key] = rawValue(serializer.Null)
+				}
+			case serializer.NullSkip:
+				continue
+			default:
+				return nil, nil, fmt.Errorf("value is null on index %d", i+1)
+			}
 
-		} else if kind == reflect.Array || kind == reflect.Slice {
+		} else {
 
-			strArray, err := s.serializeArray(&value)
-			if err != nil {
-				return serializer.Empty, err
+			value := reflect.ValueOf(genericValue)
+
+			var err error
+			if m.variables[key].key {
+				params[key], err = s.renderKey(&value)
+			} else if m.variables[key].quoted {
+				var quoted string
+				quoted, err = s.renderQuoted(&value)
+				params[key] = rawValue(quoted)
+			} else if m.variables[key].kind == reflect.Interface {
+				params[key], err = s.renderAny(&value)
+			} else {
+				params[key], err = s.renderVariable(&value)
 			}
 
-			params[key] = strArray
+			if err != nil {
+				return nil, nil, err
+			}
+		}
 
-		} else {
+		if supplied != nil {
+			supplied[key] = true
+		}
+	}
 
-			if kind == reflect.String {
+	for i := 0; i < m.numVariables; i++ {
 
-				str := value.String()
+		if supplied != nil && m.variables[i].required && !supplied[i] {
+			return nil, nil, fmt.Errorf(`required variable "%s" was not supplied`, m.variables[i].path)
+		}
 
-				var b strings.Builder
-				b.Grow(len(str) + 2 + (strings.Count(str, strDoubleQuote) * 2))
+		if params[i] == nil {
+			return nil, nil, fmt.Errorf(`variable "%s" has no default value and was not supplied`, m.variables[i].path)
+		}
+	}
 
-				b.WriteByte(byteValueDoubleQuote)
+	s.maskParameters(m, params)
 
-				for _, c := range []byte(str) {
-					if c == byteValueDoubleQuote {
-						b.WriteString(jsonEscapedDoubleQuote)
-					} else {
-						b.WriteByte(c)
-					}
-				}
+	return params, supplied, nil
+}
 
-				b.WriteByte(byteValueDoubleQuote)
+// renderVariable - renders a variable value to be used as a template parameter
+func (s *Serializer) renderVariable(value *reflect.Value) (interface{}, error) {
 
-				params[key] = b.String()
+	if encoded, ok, err := s.encodeCustom(value); ok {
+		return rawValue(encoded), err
+	}
 
-			} else {
+	if encoded, ok, err := s.encodeBytes(value); ok {
+		return rawValue(encoded), err
+	}
 
-				params[key] = genericValue
-			}
+	switch value.Kind() {
+	case reflect.Interface, reflect.Ptr:
+		return s.renderAny(value)
+	case reflect.Map:
+		if s.options.Compat {
+			return s.renderAny(value)
+		}
+		return s.serializeMap(value)
+	case reflect.Array, reflect.Slice:
+		if s.options.Compat {
+			return s.renderAny(value)
+		}
+		return s.serializeArray(value)
+	case reflect.String:
+		str := value.String()
+		var b strings.Builder
+		b.Grow(len(str) + 2 + (strings.Count(str, strDoubleQuote) * 2))
+		s.writeStringValue(str, &b)
+		return b.String(), nil
+	case reflect.Float32, reflect.Float64:
+		formatted, err := s.formatFloat(value)
+		return rawValue(formatted), err
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if s.options.Canonical {
+			formatted, err := s.formatCanonicalInteger(value.Int())
+			return rawValue(formatted), err
 		}
+		return value.Int(), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		if s.options.Canonical {
+			formatted, err := s.formatCanonicalUnsigned(value.Uint())
+			return rawValue(formatted), err
+		}
+		return value.Uint(), nil
+	case reflect.Bool:
+		return value.Bool(), nil
+	default:
+		if !value.CanInterface() {
+			return nil, fmt.Errorf("the value of type %s is not accessible", value.Type().String())
+		}
+		return value.Interface(), nil
 	}
-
-	return fmt.Sprintf(m.format, params...), nil
 }
 
 // serializeMap - serializes a map to JSON format
 func (s *Serializer) serializeMap(value *reflect.Value) (string, error) {
 
-	it := value.MapRange()
-
-	hasNext := it.Next()
+	keys, err := s.mapKeys(value)
+	if err != nil {
+		return serializer.Empty, err
+	}
 
 	var b strings.Builder
 
-	for hasNext {
+	for i, k := range keys {
 
-		key := it.Key().String()
-		val := it.Value()
+		val := value.MapIndex(k.value)
 
 		strVal, err := s.getValueFromField(nil, &val)
 		if err != nil {
 			return serializer.Empty, err
 		}
 
-		s.writeStringValue(key, &b)
+		s.writeStringValue(k.name, &b)
 		b.WriteString(strColon)
 		b.WriteString(strVal)
 
-		hasNext = it.Next()
-		if hasNext {
+		if i < len(keys)-1 {
 			b.WriteString(strComma)
 		}
 	}
@@ -1,6 +1,12 @@
 package json
 
-import "github.com/uol/serializer/serializer"
+import (
+	"fmt"
+	"reflect"
+	"sync"
+
+	"github.com/uol/serializer/serializer"
+)
 
 /**
 * Has all structs used by the JSON serializer.
@@ -30,19 +36,59 @@ const (
 	strFloatVar              string = "%f"
 	strIntVar                string = "%d"
 	strBooleanVar            string = "%t"
+	strUnicodeEscape         string = "\\u00"
+	strPercent               string = "%"
+	strEscapedPercent        string = "%%"
+	strKeyVariable           string = "@"
+	hexDigits                string = "0123456789abcdef"
 )
 
 var (
 	byteValueDoubleQuote = ([]byte(strDoubleQuote))[0]
 	byteValueEscapeBar   = ([]byte("\\"))[0]
+	bytePercent          = ([]byte("%"))[0]
 )
 
+// variable - a template variable, the kind of its value and its default (rendered) value
+type variable struct {
+	path         string
+	kind         reflect.Kind
+	defaultValue interface{}
+	required     bool
+	quoted       bool
+	key          bool
+	optional     bool
+	redactVerb   string
+}
+
 // mappedJSON - internal mapped JSON struct
 type mappedJSON struct {
 	format       string
 	formatSize   int
 	variableMap  map[string]int
 	numVariables int
+	numRequired  int
+	variables    []variable
+	fragments    []string
+	item         interface{}
+	paths        map[string]struct{}
+	indented     *indentedJSON
+	sections     []section
+	segments     []segment
+	projected    []string
+	ignored      map[string]struct{}
+	redacted     *mappedJSON
+	size         *serializer.AdaptiveSize
+	dynamic      bool
+}
+
+// rawValue - an already rendered value, written as is whatever the template verb is
+type rawValue string
+
+// Format - writes the raw value (implements fmt.Formatter)
+func (r rawValue) Format(f fmt.State, verb rune) {
+
+	f.Write([]byte(r))
 }
 
 // Point - the base point
@@ -67,8 +113,17 @@ type TextPoint struct {
 // Serializer - the json serializer
 type Serializer struct {
 	serializer.Serializer
-	bufferSize int
-	mapping    map[string]*mappedJSON
+	bufferSize   int
+	mapping      map[string]*mappedJSON
+	mappingLock  sync.RWMutex
+	types        map[reflect.Type]*mappedJSON
+	typesLock    sync.RWMutex
+	pool         *serializer.BufferPool
+	options      Options
+	encoders     map[reflect.Type]EncoderFunc
+	encodersLock sync.RWMutex
+	fields       map[reflect.Type][]structField
+	fieldsLock   sync.RWMutex
 }
 
 // ArrayItem - a configuration to render a json
@@ -80,8 +135,42 @@ type ArrayItem struct {
 // New - creates a new JSON serializer
 func New(bufferSize int) *Serializer {
 
+	return NewWithOptions(WithBufferSize(bufferSize))
+}
+
+// NewWithOptions - creates a new JSON serializer configured by the options
+func NewWithOptions(options ...Option) *Serializer {
+
+	o := Options{
+		Options:   serializer.DefaultOptions(),
+		TagKey:    strJSON,
+		Redaction: MaskValue,
+	}
+
+	for _, option := range options {
+		option(&o)
+	}
+
+	if o.Compat {
+		o.SortedKeys = true
+		o.HTMLEscape = true
+		o.TagKey = strJSON
+		o.NamingStrategy = FieldName
+		o.NullPolicy = serializer.NullWrite
+	}
+
+	if o.Canonical {
+		o.SortedKeys = true
+		o.HTMLEscape = false
+	}
+
 	return &Serializer{
-		bufferSize: bufferSize,
+		bufferSize: o.BufferSize,
 		mapping:    map[string]*mappedJSON{},
+		types:      map[reflect.Type]*mappedJSON{},
+		pool:       serializer.NewBufferPool(),
+		options:    o,
+		encoders:   map[reflect.Type]EncoderFunc{},
+		fields:     map[reflect.Type][]structField{},
 	}
 }
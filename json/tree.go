@@ -0,0 +1,181 @@
+package json
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/uol/serializer/serializer"
+)
+
+/**
+* Has the rendering of nested values (like interface{} trees) from the JSON serializer.
+* @author rnojiri
+**/
+
+const (
+	// maxTreeDepth - the maximum nesting depth of a value, deeper values are probably cyclic
+	maxTreeDepth int = 1000
+)
+
+// renderTree - renders a nested value (maps, arrays, slices, structs, pointers and interfaces) as JSON,
+// using the same rules of the typed fields
+func (s *Serializer) renderTree(value *reflect.Value, depth int) (string, error) {
+
+	if depth > maxTreeDepth {
+		return serializer.Empty, fmt.Errorf("maximum nesting depth of %d exceeded by type %s (cyclic value?)", maxTreeDepth, value.Type().String())
+	}
+
+	if encoded, ok, err := s.encodeCustom(value); ok {
+		return encoded, err
+	}
+
+	if encoded, ok, err := s.encodeBytes(value); ok {
+		return encoded, err
+	}
+
+	switch value.Kind() {
+	case reflect.Interface, reflect.Ptr:
+
+		if value.IsNil() {
+			return serializer.Null, nil
+		}
+
+		elem := value.Elem()
+
+		return s.renderTree(&elem, depth+1)
+
+	case reflect.Map:
+
+		if value.IsNil() {
+			return serializer.Null, nil
+		}
+
+		keys, err := s.mapKeys(value)
+		if err != nil {
+			return serializer.Empty, err
+		}
+
+		var b strings.Builder
+		b.WriteString(strBracketLeft)
+
+		for i, k := range keys {
+
+			val := value.MapIndex(k.value)
+
+			rendered, err := s.renderTree(&val, depth+1)
+			if err != nil {
+				return serializer.Empty, err
+			}
+
+			if i > 0 {
+				b.WriteString(strComma)
+			}
+
+			s.writeStringValue(k.name, &b)
+			b.WriteString(strColon)
+			b.WriteString(rendered)
+		}
+
+		b.WriteString(strBracketRight)
+
+		return b.String(), nil
+
+	case reflect.Array, reflect.Slice:
+
+		if value.Kind() == reflect.Slice && value.IsNil() {
+			return serializer.Null, nil
+		}
+
+		var b strings.Builder
+		b.WriteString(strSquareBracketLeft)
+
+		for i := 0; i < value.Len(); i++ {
+
+			val := value.Index(i)
+
+			rendered, err := s.renderTree(&val, depth+1)
+			if err != nil {
+				return serializer.Empty, err
+			}
+
+			if i > 0 {
+				b.WriteString(strComma)
+			}
+
+			b.WriteString(rendered)
+		}
+
+		b.WriteString(strSquareBracketRight)
+
+		return b.String(), nil
+
+	case reflect.Struct:
+
+		var b strings.Builder
+		b.WriteString(strBracketLeft)
+
+		err := s.renderStruct(value, &b, depth)
+		if err != nil {
+			return serializer.Empty, err
+		}
+
+		b.WriteString(strBracketRight)
+
+		return b.String(), nil
+
+	default:
+
+		return s.getValueFromField(nil, value)
+	}
+}
+
+// renderStruct - renders the struct properties following the same rules of the mapped structs
+func (s *Serializer) renderStruct(value *reflect.Value, b *strings.Builder, depth int) error {
+
+	written := false
+
+	for _, field := range s.typeFields(value.Type()) {
+
+		fv, ok := s.fieldByIndex(*value, field.index)
+		if !ok || s.isOmitted(&field, &fv) {
+			continue
+		}
+
+		var rendered string
+		var err error
+
+		if s.options.Compat && field.quoted {
+			rendered, err = s.renderQuoted(&fv)
+		} else {
+			rendered, err = s.renderTree(&fv, depth+1)
+		}
+
+		if err != nil {
+			return err
+		}
+
+		if written {
+			b.WriteString(strComma)
+		}
+
+		s.writeStringValue(field.name, b)
+		b.WriteString(strColon)
+		b.WriteString(rendered)
+
+		written = true
+	}
+
+	return nil
+}
+
+// renderAny - renders a value of a variable which accepts any JSON value
+func (s *Serializer) renderAny(value *reflect.Value) (interface{}, error) {
+
+	rendered, err := s.renderTree(value, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	return rawValue(rendered), nil
+}
@@ -0,0 +1,60 @@
+package opentsdb
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/uol/serializer/serializer"
+)
+
+/**
+* Has the size bounded serialization methods from the OpenTSDB serializer.
+* @author rnojiri
+**/
+
+// SerializeArrayChunked - serializes the items in a sequence of whole lines payloads with at most maxBytes each
+func (s *Serializer) SerializeArrayChunked(maxBytes int, items ...*ArrayItem) (chunks []serializer.Chunk, err error) {
+
+	defer s.options.Recover(&err)
+
+	chunks = []serializer.Chunk{}
+
+	var b strings.Builder
+	var indexes []int
+
+	line := s.pool.Get(s.lineSize.Get())
+	defer s.pool.Put(line)
+
+	for i := 0; i < len(items); i++ {
+
+		line.Reset()
+
+		err := s.serializeLine(line, items[i].Metric, items[i].Timestamp, items[i].Value, items[i].Tags...)
+		if err != nil {
+			return nil, err
+		}
+
+		if line.Len() > maxBytes {
+			return nil, fmt.Errorf("item on index %d exceeds the limit of %d bytes", i, maxBytes)
+		}
+
+		if len(indexes) > 0 && b.Len()+line.Len() > maxBytes {
+			chunks = append(chunks, serializer.Chunk{Payload: b.String(), Indexes: indexes})
+			b = strings.Builder{}
+			indexes = nil
+		}
+
+		if len(indexes) == 0 {
+			b.Grow(s.bufferSize)
+		}
+
+		b.WriteString(line.String())
+		indexes = append(indexes, i)
+	}
+
+	if len(indexes) > 0 {
+		chunks = append(chunks, serializer.Chunk{Payload: b.String(), Indexes: indexes})
+	}
+
+	return chunks, nil
+}
@@ -0,0 +1,114 @@
+package opentsdb
+
+import (
+	"fmt"
+	"io"
+)
+
+/**
+* Has the incremental array encoder from the OpenTSDB serializer.
+* @author rnojiri
+**/
+
+// ArrayEncoder - serializes an unbounded sequence of opentsdb data lines incrementally, reusing an internal buffer
+type ArrayEncoder struct {
+	serializer *Serializer
+	writer     io.Writer
+	buffer     []byte
+	numPending int
+	maxBytes   int
+	maxItems   int
+	started    bool
+}
+
+// NewArrayEncoder - creates a new array encoder, the buffer is flushed automatically before exceeding
+// maxBytes or when maxItems are buffered (zero disables the threshold)
+func (s *Serializer) NewArrayEncoder(maxBytes, maxItems int) *ArrayEncoder {
+
+	return &ArrayEncoder{
+		serializer: s,
+		buffer:     make([]byte, 0, s.bufferSize),
+		maxBytes:   maxBytes,
+		maxItems:   maxItems,
+	}
+}
+
+// Begin - starts a new sequence of lines to be written in the writer
+func (e *ArrayEncoder) Begin(w io.Writer) error {
+
+	if e.started {
+		return fmt.Errorf("the array encoder was already started")
+	}
+
+	e.writer = w
+	e.buffer = e.buffer[:0]
+	e.numPending = 0
+	e.started = true
+
+	return nil
+}
+
+// Add - serializes the item and adds its line
+func (e *ArrayEncoder) Add(item *ArrayItem) error {
+
+	if !e.started {
+		return fmt.Errorf("the array encoder was not started")
+	}
+
+	result, err := e.serializer.Serialize(item.Metric, item.Timestamp, item.Value, item.Tags...)
+	if err != nil {
+		return err
+	}
+
+	if e.maxBytes > 0 && e.numPending > 0 && len(e.buffer)+len(result) > e.maxBytes {
+		err = e.Flush()
+		if err != nil {
+			return err
+		}
+	}
+
+	e.buffer = append(e.buffer, result...)
+	e.numPending++
+
+	if e.maxItems > 0 && e.numPending >= e.maxItems {
+		return e.Flush()
+	}
+
+	return nil
+}
+
+// Flush - writes the buffered lines to the writer
+func (e *ArrayEncoder) Flush() error {
+
+	if !e.started {
+		return fmt.Errorf("the array encoder was not started")
+	}
+
+	if len(e.buffer) == 0 {
+		return nil
+	}
+
+	_, err := e.writer.Write(e.buffer)
+	if err != nil {
+		return err
+	}
+
+	e.buffer = e.buffer[:0]
+	e.numPending = 0
+
+	return nil
+}
+
+// End - flushes the buffered lines and finishes the sequence
+func (e *ArrayEncoder) End() error {
+
+	err := e.Flush()
+	if err != nil {
+		return err
+	}
+
+	e.started = false
+	e.writer = nil
+
+	return nil
+}
@@ -0,0 +1,74 @@
+package opentsdb
+
+import "github.com/uol/serializer/serializer"
+
+/**
+* Has all options used by the OpenTSDB serializer.
+* @author rnojiri
+**/
+
+// Options - the OpenTSDB serializer options
+type Options struct {
+	serializer.Options
+	SortedKeys bool
+}
+
+// Option - an option used when creating a new serializer
+type Option func(options *Options)
+
+// WithBufferSize - sets the initial buffer size
+func WithBufferSize(size int) Option {
+
+	return func(options *Options) {
+		options.BufferSize = size
+	}
+}
+
+// WithSortedKeys - writes the tags sorted by key, making the output deterministic
+func WithSortedKeys() Option {
+
+	return func(options *Options) {
+		options.SortedKeys = true
+	}
+}
+
+// WithFloatFormat - sets the format and precision of the value and float tags (see strconv.FormatFloat)
+func WithFloatFormat(format byte, precision int) Option {
+
+	return func(options *Options) {
+		options.FloatFormat = format
+		options.FloatPrecision = precision
+	}
+}
+
+// WithNullPolicy - sets how the null tag values are handled
+func WithNullPolicy(policy serializer.NullPolicy) Option {
+
+	return func(options *Options) {
+		options.NullPolicy = policy
+	}
+}
+
+// WithPanicPolicy - sets how the unexpected panics are handled
+func WithPanicPolicy(policy serializer.PanicPolicy) Option {
+
+	return func(options *Options) {
+		options.PanicPolicy = policy
+	}
+}
+
+// WithLogger - sets the logger used to report the recovered panics
+func WithLogger(logger serializer.Logger) Option {
+
+	return func(options *Options) {
+		options.Logger = logger
+	}
+}
+
+// WithMaxOutputSize - sets the maximum size in bytes of a serialized output, bigger ones return an error
+func WithMaxOutputSize(size int) Option {
+
+	return func(options *Options) {
+		options.MaxOutputSize = size
+	}
+}
@@ -0,0 +1,68 @@
+package opentsdb
+
+import (
+	"strings"
+	"sync"
+
+	"github.com/uol/serializer/serializer"
+)
+
+/**
+* Has the parallel serialization methods from the OpenTSDB serializer.
+* @author rnojiri
+**/
+
+// SerializeArrayParallel - serializes an array of opentsdb data lines splitting the items in shards serialized concurrently,
+// the output is the same of SerializeArray
+func (s *Serializer) SerializeArrayParallel(workers int, items ...*ArrayItem) (string, error) {
+
+	numItems := len(items)
+	if workers <= 1 || numItems < 2 {
+		return s.SerializeArray(items...)
+	}
+
+	shards := serializer.Shards(workers, numItems)
+	results := make([]string, len(shards))
+	shardErrors := make([]error, len(shards))
+
+	var wg sync.WaitGroup
+	wg.Add(len(shards))
+
+	for shard := range shards {
+
+		go func(shard int) {
+
+			defer wg.Done()
+
+			results[shard], shardErrors[shard] = s.SerializeArray(items[shards[shard][0]:shards[shard][1]]...)
+
+		}(shard)
+	}
+
+	wg.Wait()
+
+	totalSize := 0
+
+	for shard := range shards {
+
+		if shardErrors[shard] != nil {
+			return serializer.Empty, shardErrors[shard]
+		}
+
+		totalSize += len(results[shard])
+	}
+
+	err := s.options.CheckOutputSize(totalSize)
+	if err != nil {
+		return serializer.Empty, err
+	}
+
+	var b strings.Builder
+	b.Grow(totalSize)
+
+	for shard := range shards {
+		b.WriteString(results[shard])
+	}
+
+	return b.String(), nil
+}
@@ -1,8 +1,10 @@
 package opentsdb
 
 import (
+	"bytes"
 	"fmt"
 	"reflect"
+	"sort"
 	"strconv"
 	"strings"
 
@@ -58,38 +60,82 @@ func (s *Serializer) SerializeGenericArray(items ...interface{}) (string, error)
 }
 
 // SerializeArray - serializes an array of opentsdb data lines
-func (s *Serializer) SerializeArray(items ...*ArrayItem) (string, error) {
+func (s *Serializer) SerializeArray(items ...*ArrayItem) (result string, err error) {
 
-	defer serializer.PanicHandler()
+	defer s.options.Recover(&err)
 
 	numItems := len(items)
 	if numItems == 0 {
 		return serializer.Empty, nil
 	}
 
-	var b strings.Builder
-	b.Grow(s.bufferSize * numItems)
+	b := s.pool.Get(s.lineSize.Get() * numItems)
+	defer s.pool.Put(b)
 
-	var err error
 	for i := 0; i < numItems; i++ {
-		err = s.serializeLine(&b, items[i].Metric, items[i].Timestamp, items[i].Value, items[i].Tags...)
+		err = s.serializeLine(b, items[i].Metric, items[i].Timestamp, items[i].Value, items[i].Tags...)
 		if err != nil {
 			return serializer.Empty, err
 		}
 	}
 
+	s.lineSize.Observe(b.Len() / numItems)
+
+	err = s.options.CheckOutputSize(b.Len())
+	if err != nil {
+		return serializer.Empty, err
+	}
+
 	return b.String(), nil
 }
 
-// Serialize - serializes an opentsdb data line
-func (s *Serializer) Serialize(metric string, timestamp int64, value float64, tags ...interface{}) (string, error) {
+// SerializeArrayLenient - serializes an array of opentsdb data lines skipping the invalid items, a *serializer.BatchError is returned listing them
+// (an empty payload is returned when no item is serialized)
+func (s *Serializer) SerializeArrayLenient(items ...*ArrayItem) (result string, err error) {
+
+	defer s.options.Recover(&err)
 
-	defer serializer.PanicHandler()
+	numItems := len(items)
+	batchErr := &serializer.BatchError{}
 
 	var b strings.Builder
-	b.Grow(s.bufferSize)
+	b.Grow(s.bufferSize * numItems)
+
+	for i := 0; i < numItems; i++ {
+
+		line, err := s.Serialize(items[i].Metric, items[i].Timestamp, items[i].Value, items[i].Tags...)
+		if err != nil {
+			batchErr.Add(i, err)
+			continue
+		}
+
+		b.WriteString(line)
+	}
+
+	err = s.options.CheckOutputSize(b.Len())
+	if err != nil {
+		return serializer.Empty, err
+	}
+
+	return b.String(), batchErr.ErrorOrNil()
+}
+
+// Serialize - serializes an opentsdb data line
+func (s *Serializer) Serialize(metric string, timestamp int64, value float64, tags ...interface{}) (result string, err error) {
+
+	defer s.options.Recover(&err)
 
-	err := s.serializeLine(&b, metric, timestamp, value, tags...)
+	b := s.pool.Get(s.lineSize.Get())
+	defer s.pool.Put(b)
+
+	err = s.serializeLine(b, metric, timestamp, value, tags...)
+	if err != nil {
+		return serializer.Empty, err
+	}
+
+	s.lineSize.Observe(b.Len())
+
+	err = s.options.CheckOutputSize(b.Len())
 	if err != nil {
 		return serializer.Empty, err
 	}
@@ -97,8 +143,14 @@ func (s *Serializer) Serialize(metric string, timestamp int64, value float64, ta
 	return b.String(), nil
 }
 
+// PoolStats - returns the buffer pool statistics
+func (s *Serializer) PoolStats() serializer.PoolStats {
+
+	return s.pool.Stats()
+}
+
 // serializeLine - serializes an opentsdb data line (internal)
-func (s *Serializer) serializeLine(b *strings.Builder, metric string, timestamp int64, value float64, tags ...interface{}) error {
+func (s *Serializer) serializeLine(b *bytes.Buffer, metric string, timestamp int64, value float64, tags ...interface{}) error {
 
 	numTags := len(tags)
 
@@ -111,10 +163,12 @@ func (s *Serializer) serializeLine(b *strings.Builder, metric string, timestamp
 	b.WriteString(strSpace)
 	b.WriteString(strconv.FormatInt(timestamp, 10))
 	b.WriteString(strSpace)
-	b.WriteString(strconv.FormatFloat(value, serializer.ByteFloatFormat, -1, 64))
+	b.WriteString(s.options.FormatFloat(value))
 	b.WriteString(strSpace)
 
-	for i := 0; i < numTags; i += 2 {
+	written := 0
+
+	for _, i := range s.tagOrder(tags) {
 
 		if serializer.InterfaceHasZeroValue(tags[i]) {
 			return fmt.Errorf("tag name is null on index %d", i)
@@ -127,22 +181,36 @@ func (s *Serializer) serializeLine(b *strings.Builder, metric string, timestamp
 
 		tagValue := tags[i+1]
 
+		value := serializer.Null
+
 		if serializer.InterfaceHasZeroValue(tagValue) {
-			return fmt.Errorf("tag value is null on index %d", i+i)
+
+			switch s.options.NullPolicy {
+			case serializer.NullWrite:
+			case serializer.NullSkip:
+				continue
+			default:
+				return fmt.Errorf("tag value is null on index %d", i+i)
+			}
+
+		} else {
+
+			var err error
+			value, err = s.writeValue(tagValue)
+			if err != nil {
+				return err
+			}
 		}
 
-		value, err := s.writeValue(tagValue)
-		if err != nil {
-			return err
+		if written > 0 {
+			b.WriteString(strSpace)
 		}
 
 		b.WriteString(key)
 		b.WriteString(strEqual)
 		b.WriteString(value)
 
-		if i < numTags-2 {
-			b.WriteString(strSpace)
-		}
+		written++
 	}
 
 	b.WriteByte(byteLineSeparator)
@@ -163,13 +231,34 @@ func (s *Serializer) writeValue(tagValue interface{}) (string, error) {
 	switch kind {
 	case reflect.String:
 		return value.String(), nil
-	case reflect.Uint, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uint8, reflect.Uintptr, reflect.Int, reflect.Int16, reflect.Int32, reflect.Int64, reflect.Int8:
+	case reflect.Uint, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uint8, reflect.Uintptr:
+		return strconv.FormatUint(value.Uint(), 10), nil
+	case reflect.Int, reflect.Int16, reflect.Int32, reflect.Int64, reflect.Int8:
 		return strconv.FormatInt(value.Int(), 10), nil
 	case reflect.Float32, reflect.Float64:
-		return strconv.FormatFloat(value.Float(), serializer.ByteFloatFormat, -1, 64), nil
+		return s.options.FormatFloat(value.Float()), nil
 	case reflect.Bool:
 		return strconv.FormatBool(value.Bool()), nil
 	default:
 		return serializer.Empty, fmt.Errorf("kind not mapped: %s", kind.String())
 	}
 }
+
+// tagOrder - returns the index of each tag key, sorted by key if the option is set
+func (s *Serializer) tagOrder(tags []interface{}) []int {
+
+	order := make([]int, 0, len(tags)/2)
+	for i := 0; i < len(tags); i += 2 {
+		order = append(order, i)
+	}
+
+	if s.options.SortedKeys {
+		sort.SliceStable(order, func(i, j int) bool {
+			keyI, _ := tags[order[i]].(string)
+			keyJ, _ := tags[order[j]].(string)
+			return keyI < keyJ
+		})
+	}
+
+	return order
+}
@@ -11,6 +11,9 @@ import "github.com/uol/serializer/serializer"
 type Serializer struct {
 	serializer.Serializer
 	bufferSize int
+	pool       *serializer.BufferPool
+	lineSize   *serializer.AdaptiveSize
+	options    Options
 }
 
 // ArrayItem - an array of parameters
@@ -24,7 +27,24 @@ type ArrayItem struct {
 // New - creates a new JSON serializer
 func New(bufferSize int) *Serializer {
 
+	return NewWithOptions(WithBufferSize(bufferSize))
+}
+
+// NewWithOptions - creates a new OpenTSDB serializer configured by the options
+func NewWithOptions(options ...Option) *Serializer {
+
+	o := Options{
+		Options: serializer.DefaultOptions(),
+	}
+
+	for _, option := range options {
+		option(&o)
+	}
+
 	return &Serializer{
-		bufferSize: bufferSize,
+		bufferSize: o.BufferSize,
+		pool:       serializer.NewBufferPool(),
+		lineSize:   serializer.NewAdaptiveSize(o.BufferSize),
+		options:    o,
 	}
 }
@@ -1,4 +1,6 @@
 #!/bin/bash
 
-go test -v -count 1 ./tests/opentsdb/opentsdb_test.go
-go test -v -count 1 ./tests/json/json_test.go
\ No newline at end of file
+go test -v -count 1 ./tests/opentsdb/
+go test -v -count 1 ./tests/json/
+go test -v -count 1 ./tests/generated/
+go test -v -count 1 ./tests/serializer/
@@ -0,0 +1,128 @@
+package serializer
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+)
+
+/**
+* Has the options shared by all serializers.
+* @author rnojiri
+**/
+
+// NullPolicy - defines how the null (nil) values are handled
+type NullPolicy uint8
+
+const (
+	// NullError - null values are rejected with an error (default)
+	NullError NullPolicy = 0
+
+	// NullWrite - null values are written as "null" (the JSON map and array variables are written empty)
+	NullWrite NullPolicy = 1
+
+	// NullSkip - null values are skipped, the JSON variables keep their default values and the OpenTSDB tags are omitted
+	NullSkip NullPolicy = 2
+)
+
+// PanicPolicy - defines how the unexpected panics are handled
+type PanicPolicy uint8
+
+const (
+	// PanicRecover - the panic is recovered and logged, an empty result is returned (default)
+	PanicRecover PanicPolicy = 0
+
+	// PanicAsError - the panic is recovered, logged and returned as an error
+	PanicAsError PanicPolicy = 1
+
+	// PanicPropagate - the panic is not recovered
+	PanicPropagate PanicPolicy = 2
+)
+
+// Logger - the logger used to report the recovered panics (*log.Logger implements it)
+type Logger interface {
+	Printf(format string, v ...interface{})
+}
+
+// Options - the options shared by all serializers
+type Options struct {
+	BufferSize     int
+	FloatFormat    byte
+	FloatPrecision int
+	NullPolicy     NullPolicy
+	PanicPolicy    PanicPolicy
+	Logger         Logger
+	MaxOutputSize  int
+}
+
+// DefaultOptions - returns the default options
+func DefaultOptions() Options {
+
+	return Options{
+		FloatFormat:    ByteFloatFormat,
+		FloatPrecision: -1,
+		NullPolicy:     NullError,
+		PanicPolicy:    PanicRecover,
+		Logger:         log.New(os.Stdout, Empty, 0),
+	}
+}
+
+// FormatFloat - formats a float using the configured format and precision
+func (o *Options) FormatFloat(value float64) string {
+
+	return strconv.FormatFloat(value, o.FloatFormat, o.FloatPrecision, 64)
+}
+
+// CustomFloatFormat - returns if the float format was changed from the default one
+func (o *Options) CustomFloatFormat() bool {
+
+	return o.FloatFormat != ByteFloatFormat || o.FloatPrecision != -1
+}
+
+// CheckOutputSize - returns an error if the output size exceeds the maximum (zero means no limit)
+func (o *Options) CheckOutputSize(size int) error {
+
+	if o.MaxOutputSize > 0 && size > o.MaxOutputSize {
+		return fmt.Errorf("output of %d bytes exceeds the maximum of %d bytes", size, o.MaxOutputSize)
+	}
+
+	return nil
+}
+
+// Recover - handles an unexpected panic following the panic policy, must be deferred
+func (o *Options) Recover(err *error) {
+
+	if o.PanicPolicy == PanicPropagate {
+		return
+	}
+
+	r := recover()
+	if r == nil {
+		return
+	}
+
+	o.Logger.Printf("[critical error] unexpected error on serializer library: %v", r)
+
+	if o.PanicPolicy == PanicAsError {
+		*err = fmt.Errorf("unexpected error on serializer library: %v", r)
+	}
+}
+
+// RecoverAsError - handles an unexpected panic returning it as an error unless the policy is PanicPropagate, used by
+// the methods serializing many items so a failed item is never written as an empty value, must be deferred
+func (o *Options) RecoverAsError(err *error) {
+
+	if o.PanicPolicy == PanicPropagate {
+		return
+	}
+
+	r := recover()
+	if r == nil {
+		return
+	}
+
+	o.Logger.Printf("[critical error] unexpected error on serializer library: %v", r)
+
+	*err = fmt.Errorf("unexpected error on serializer library: %v", r)
+}
@@ -0,0 +1,122 @@
+package serializer
+
+import (
+	"bytes"
+	"sync"
+	"sync/atomic"
+)
+
+/**
+* Has the buffer pooling used by the serializers.
+* @author rnojiri
+**/
+
+const (
+	// MaxPooledBufferSize - buffers bigger than this size are not returned to the pool
+	MaxPooledBufferSize int = 1024 * 1024
+
+	// adaptiveSizeWeight - the weight (power of 2) of the new observations in the running average
+	adaptiveSizeWeight uint = 3
+)
+
+// PoolStats - the buffer pool statistics
+type PoolStats struct {
+	Hits   uint64
+	Misses uint64
+}
+
+// BufferPool - a sync.Pool backed buffer pool with hit and miss statistics
+type BufferPool struct {
+	gets   uint64
+	misses uint64
+	pool   sync.Pool
+}
+
+// NewBufferPool - creates a new buffer pool
+func NewBufferPool() *BufferPool {
+
+	p := &BufferPool{}
+	p.pool.New = func() interface{} {
+		atomic.AddUint64(&p.misses, 1)
+		return &bytes.Buffer{}
+	}
+
+	return p
+}
+
+// Get - returns an empty buffer with at least the given capacity
+func (p *BufferPool) Get(size int) *bytes.Buffer {
+
+	atomic.AddUint64(&p.gets, 1)
+
+	b := p.pool.Get().(*bytes.Buffer)
+	b.Grow(size)
+
+	return b
+}
+
+// Put - resets the buffer and returns it to the pool
+func (p *BufferPool) Put(b *bytes.Buffer) {
+
+	if b.Cap() > MaxPooledBufferSize {
+		return
+	}
+
+	b.Reset()
+	p.pool.Put(b)
+}
+
+// Stats - returns the pool statistics
+func (p *BufferPool) Stats() PoolStats {
+
+	// the gets are counted before the misses, loading the misses first the hits never underflow
+	misses := atomic.LoadUint64(&p.misses)
+	gets := atomic.LoadUint64(&p.gets)
+
+	return PoolStats{
+		Hits:   gets - misses,
+		Misses: misses,
+	}
+}
+
+// AdaptiveSize - a running average of the observed output sizes, used to size the buffers
+type AdaptiveSize struct {
+	value int64
+}
+
+// NewAdaptiveSize - creates a new adaptive size starting with the initial value
+func NewAdaptiveSize(initial int) *AdaptiveSize {
+
+	return &AdaptiveSize{
+		value: int64(initial),
+	}
+}
+
+// Get - returns the current size
+func (a *AdaptiveSize) Get() int {
+
+	return int(atomic.LoadInt64(&a.value))
+}
+
+// Observe - adds a new observed size to the running average
+func (a *AdaptiveSize) Observe(size int) {
+
+	observed := int64(size)
+
+	for {
+		current := atomic.LoadInt64(&a.value)
+		if current == observed {
+			return
+		}
+
+		// rounds up when growing, so small differences are not ignored
+		next := current + (observed-current)>>adaptiveSizeWeight
+		if observed > current && next < observed {
+			next++
+		}
+
+		if atomic.CompareAndSwapInt64(&a.value, current, next) {
+			return
+		}
+	}
+}
@@ -26,6 +26,91 @@ type Serializer interface {
 	SerializeGenericArray(item ...interface{}) (string, error)
 }
 
+// Chunk - a complete serialized payload and the indexes of the items it contains
+type Chunk struct {
+	Payload string
+	Indexes []int
+}
+
+// ItemError - the error of a single item from a batch
+type ItemError struct {
+	Index int
+	Err   error
+}
+
+// Error - returns the error message
+func (e *ItemError) Error() string {
+
+	return fmt.Sprintf("item on index %d: %s", e.Index, e.Err.Error())
+}
+
+// Unwrap - returns the item error cause
+func (e *ItemError) Unwrap() error {
+
+	return e.Err
+}
+
+// BatchError - lists the items which failed in a batch serialization
+type BatchError struct {
+	Errors []*ItemError
+}
+
+// Error - returns the error message
+func (e *BatchError) Error() string {
+
+	if len(e.Errors) == 1 {
+		return fmt.Sprintf("1 item failed: %s", e.Errors[0].Error())
+	}
+
+	return fmt.Sprintf("%d items failed, first %s", len(e.Errors), e.Errors[0].Error())
+}
+
+// Add - adds a new item error
+func (e *BatchError) Add(index int, err error) {
+
+	e.Errors = append(e.Errors, &ItemError{Index: index, Err: err})
+}
+
+// ErrorOrNil - returns the batch error only if any item has failed
+func (e *BatchError) ErrorOrNil() error {
+
+	if len(e.Errors) == 0 {
+		return nil
+	}
+
+	return e
+}
+
+// Shards - splits the items in contiguous ranges (start and end, exclusive), one for each worker
+func Shards(workers, numItems int) [][2]int {
+
+	if workers > numItems {
+		workers = numItems
+	}
+
+	if workers < 1 {
+		return nil
+	}
+
+	shards := make([][2]int, workers)
+	size := numItems / workers
+	remainder := numItems % workers
+	start := 0
+
+	for i := 0; i < workers; i++ {
+
+		end := start + size
+		if i < remainder {
+			end++
+		}
+
+		shards[i] = [2]int{start, end}
+		start = end
+	}
+
+	return shards
+}
+
 // InterfaceHasZeroValue - is a value from a interface zero? (nil)
 func InterfaceHasZeroValue(x interface{}) bool {
 
@@ -0,0 +1,190 @@
+package generated
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	gotest "github.com/uol/gotest/utils"
+	jsonserializer "github.com/uol/serializer/json"
+	opentsdbserializer "github.com/uol/serializer/opentsdb"
+)
+
+/**
+* Has unit tests comparing the generated serializers against the template serializers.
+* @author rnojiri
+**/
+
+// marshal - marshals the item using the template serializer (the generated code always writes the map keys sorted)
+func marshal(t *testing.T, item interface{}) string {
+
+	result, err := jsonserializer.NewWithOptions(jsonserializer.WithBufferSize(100), jsonserializer.WithSortedKeys()).Marshal(item)
+	if !assert.NoError(t, err, "error marshalling the item: %+v", item) {
+		panic(err)
+	}
+
+	return result
+}
+
+// TestGeneratedSimpleJSON - compares the generated JSON with a simple struct
+func TestGeneratedSimpleJSON(t *testing.T) {
+
+	item := SimpleJSON{
+		Text:    `"generated"\`,
+		Integer: gotest.RandomInt(-1000, 1000),
+		Float:   float64(gotest.RandomInt(0, 100)) + 0.25,
+		Boolean: true,
+	}
+
+	assert.Equal(t, marshal(t, item), string(item.AppendJSON(nil)), "expected same output")
+}
+
+// TestGeneratedComplexTypeJSON - compares the generated JSON with a struct with sub objects, maps and arrays
+func TestGeneratedComplexTypeJSON(t *testing.T) {
+
+	item := ComplexTypeJSON{
+		Simple: SimpleJSON{
+			Text:    "complex",
+			Integer: -9,
+			Float:   99.5,
+		},
+		CollectionJSON: CollectionJSON{
+			Mapping: map[string]int{"1": 1},
+			Array:   []float64{1.5, 2, 3.25},
+		},
+		Unsigned: 65535,
+		Small:    0.1,
+		Host:     "localhost",
+		Ignored:  "ignored",
+	}
+
+	assert.Equal(t, marshal(t, item), string(item.AppendJSON(nil)), "expected same output")
+
+	item.CollectionJSON.Mapping = map[string]int{"b": 2, "10": 10, "a": 1, "1": 1, "B": 0}
+
+	expected := marshal(t, item)
+	assert.Contains(t, expected, `"mapping":{"1":1,"10":10,"B":0,"a":1,"b":2}`, "expected the sorted keys in the template serializer")
+
+	for i := 0; i < 10; i++ {
+		assert.Equal(t, expected, string(item.AppendJSON(nil)), "expected same output with the sorted keys")
+	}
+
+	item.CollectionJSON = CollectionJSON{}
+
+	assert.Equal(t, marshal(t, item), string(item.AppendJSON(nil)), "expected same output with empty collections")
+}
+
+// TestGeneratedDefaultOptions - compares the generated JSON with the template serializer using the default options
+// (same output without maps with many keys) and with encoding/json, which writes the map keys sorted too (the
+// untagged fields are not written by the serializers)
+func TestGeneratedDefaultOptions(t *testing.T) {
+
+	item := ComplexTypeJSON{
+		Simple: SimpleJSON{
+			Text:    "line\nbreak\t",
+			Integer: 1,
+			Float:   1e-9,
+		},
+		CollectionJSON: CollectionJSON{
+			Mapping: map[string]int{"1": 1},
+			Array:   []float64{2.5, 1e21, 123456789.125},
+		},
+		Small: 0.1,
+	}
+
+	generated := string(item.AppendJSON(nil))
+
+	expected, err := jsonserializer.New(100).Marshal(item)
+	if assert.NoError(t, err, "error marshalling the item") {
+		assert.Equal(t, expected, generated, "expected the output of the default options")
+	}
+
+	point := MetricPoint{
+		Metric: "line\nbreak",
+		Value:  1e-9,
+		Tags:   map[string]string{"b": "2", "a": "1", "c": "3"},
+	}
+
+	std, err := json.Marshal(point)
+	if assert.NoError(t, err, "error marshalling the item") {
+		assert.Equal(t, string(std), string(point.AppendJSON(nil)), "expected the output of encoding/json")
+	}
+}
+
+// TestGeneratedAppend - checks if the generated JSON is appended to the destination
+func TestGeneratedAppend(t *testing.T) {
+
+	item := SimpleJSON{Text: "append"}
+	prefix := []byte("prefix:")
+
+	assert.Equal(t, "prefix:"+marshal(t, item), string(item.AppendJSON(prefix)), "expected the prefix to be kept")
+}
+
+// TestGeneratedOpenTSDB - compares the generated OpenTSDB line with the template serializer
+func TestGeneratedOpenTSDB(t *testing.T) {
+
+	item := MetricPoint{
+		Metric:    "generated.metric",
+		Timestamp: time.Now().Unix(),
+		Value:     float64(gotest.RandomInt(0, 100)) + 1e-9,
+		Host:      "localhost",
+		TTL:       1,
+		Tags: map[string]string{
+			"ksid": "keyset",
+			"app":  "test",
+		},
+	}
+
+	expected, err := opentsdbserializer.New(100).Serialize(item.Metric, item.Timestamp, item.Value,
+		"host", string(item.Host),
+		"ttl", item.TTL,
+		"app", item.Tags["app"],
+		"ksid", item.Tags["ksid"],
+	)
+	if !assert.NoError(t, err, "error serializing the line") {
+		return
+	}
+
+	assert.Equal(t, expected, string(item.AppendOpenTSDB(nil)), "expected same output")
+
+	item.Tags = map[string]string{"ksid": "keyset"}
+
+	assert.Equal(t, marshal(t, item), string(item.AppendJSON(nil)), "expected same json output")
+}
+
+// TestGeneratedSourceIsUpdated - checks if the committed generated source matches the generator output
+func TestGeneratedSourceIsUpdated(t *testing.T) {
+
+	dir, err := ioutil.TempDir("", "serializergen")
+	if !assert.NoError(t, err, "error creating a temporary directory") {
+		return
+	}
+
+	defer os.RemoveAll(dir)
+
+	output := filepath.Join(dir, "serializer_generated.go")
+
+	command := exec.Command("go", "run", "../../cmd/serializergen", "-output", output, ".")
+	result, err := command.CombinedOutput()
+	if !assert.NoError(t, err, "error running the generator: %s", string(result)) {
+		return
+	}
+
+	expected, err := ioutil.ReadFile("serializer_generated.go")
+	if !assert.NoError(t, err, "error reading the committed generated source") {
+		return
+	}
+
+	actual, err := ioutil.ReadFile(output)
+	if !assert.NoError(t, err, "error reading the generated source") {
+		return
+	}
+
+	assert.Equal(t, string(expected), string(actual), "expected the generated source to be updated, run go generate")
+}
@@ -0,0 +1,146 @@
+// Code generated by serializergen. DO NOT EDIT.
+
+package generated
+
+import (
+	"sort"
+	"strconv"
+
+	jsonserializer "github.com/uol/serializer/json"
+)
+
+// AppendJSON - appends the JSON representation of SimpleJSON to dst
+func (x *SimpleJSON) AppendJSON(dst []byte) []byte {
+	dst = append(dst, "{\"text\":"...)
+	dst = jsonserializer.AppendString(dst, x.Text)
+	dst = append(dst, ",\"integer\":"...)
+	dst = strconv.AppendInt(dst, int64(x.Integer), 10)
+	dst = append(dst, ",\"float\":"...)
+	dst = jsonserializer.AppendFloat(dst, x.Float, 64)
+	dst = append(dst, ",\"boolean\":"...)
+	dst = strconv.AppendBool(dst, x.Boolean)
+	dst = append(dst, '}')
+	return dst
+}
+
+// AppendJSON - appends the JSON representation of ComplexTypeJSON to dst
+func (x *ComplexTypeJSON) AppendJSON(dst []byte) []byte {
+	dst = append(dst, "{\"simple\":{\"text\":"...)
+	dst = jsonserializer.AppendString(dst, x.Simple.Text)
+	dst = append(dst, ",\"integer\":"...)
+	dst = strconv.AppendInt(dst, int64(x.Simple.Integer), 10)
+	dst = append(dst, ",\"float\":"...)
+	dst = jsonserializer.AppendFloat(dst, x.Simple.Float, 64)
+	dst = append(dst, ",\"boolean\":"...)
+	dst = strconv.AppendBool(dst, x.Simple.Boolean)
+	dst = append(dst, "},\"mapping\":"...)
+	if x.CollectionJSON.Mapping == nil {
+		dst = append(dst, "null"...)
+	} else {
+		dst = append(dst, '{')
+		keys := make([]string, 0, len(x.CollectionJSON.Mapping))
+		for k := range x.CollectionJSON.Mapping {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for i, k := range keys {
+			if i > 0 {
+				dst = append(dst, ',')
+			}
+			dst = jsonserializer.AppendString(dst, k)
+			dst = append(dst, ':')
+			dst = strconv.AppendInt(dst, int64(x.CollectionJSON.Mapping[k]), 10)
+		}
+		dst = append(dst, '}')
+	}
+	dst = append(dst, ",\"array\":"...)
+	if x.CollectionJSON.Array == nil {
+		dst = append(dst, "null"...)
+	} else {
+		dst = append(dst, '[')
+		for i := 0; i < len(x.CollectionJSON.Array); i++ {
+			if i > 0 {
+				dst = append(dst, ',')
+			}
+			dst = jsonserializer.AppendFloat(dst, x.CollectionJSON.Array[i], 64)
+		}
+		dst = append(dst, ']')
+	}
+	dst = append(dst, ",\"unsigned\":"...)
+	dst = strconv.AppendUint(dst, uint64(x.Unsigned), 10)
+	dst = append(dst, ",\"small\":"...)
+	dst = jsonserializer.AppendFloat(dst, float64(x.Small), 32)
+	dst = append(dst, ",\"host\":"...)
+	dst = jsonserializer.AppendString(dst, string(x.Host))
+	dst = append(dst, '}')
+	return dst
+}
+
+// AppendJSON - appends the JSON representation of MetricPoint to dst
+func (x *MetricPoint) AppendJSON(dst []byte) []byte {
+	dst = append(dst, "{\"metric\":"...)
+	dst = jsonserializer.AppendString(dst, x.Metric)
+	dst = append(dst, ",\"timestamp\":"...)
+	dst = strconv.AppendInt(dst, x.Timestamp, 10)
+	dst = append(dst, ",\"value\":"...)
+	dst = jsonserializer.AppendFloat(dst, x.Value, 64)
+	dst = append(dst, ",\"host\":"...)
+	dst = jsonserializer.AppendString(dst, string(x.Host))
+	dst = append(dst, ",\"ttl\":"...)
+	dst = strconv.AppendInt(dst, int64(x.TTL), 10)
+	dst = append(dst, ",\"tags\":"...)
+	if x.Tags == nil {
+		dst = append(dst, "null"...)
+	} else {
+		dst = append(dst, '{')
+		keys := make([]string, 0, len(x.Tags))
+		for k := range x.Tags {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for i, k := range keys {
+			if i > 0 {
+				dst = append(dst, ',')
+			}
+			dst = jsonserializer.AppendString(dst, k)
+			dst = append(dst, ':')
+			dst = jsonserializer.AppendString(dst, x.Tags[k])
+		}
+		dst = append(dst, '}')
+	}
+	dst = append(dst, '}')
+	return dst
+}
+
+// AppendOpenTSDB - appends the OpenTSDB line of MetricPoint to dst
+func (x *MetricPoint) AppendOpenTSDB(dst []byte) []byte {
+	dst = append(dst, "put "...)
+	dst = append(dst, x.Metric...)
+	dst = append(dst, ' ')
+	dst = strconv.AppendInt(dst, int64(x.Timestamp), 10)
+	dst = append(dst, ' ')
+	dst = strconv.AppendFloat(dst, float64(x.Value), 'f', -1, 64)
+	dst = append(dst, " host="...)
+	dst = append(dst, string(x.Host)...)
+	dst = append(dst, " ttl="...)
+	dst = strconv.AppendInt(dst, int64(x.TTL), 10)
+	hasTags := true
+	{
+		keys := make([]string, 0, len(x.Tags))
+		for k := range x.Tags {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			if hasTags {
+				dst = append(dst, ' ')
+			}
+			hasTags = true
+			dst = append(dst, k...)
+			dst = append(dst, '=')
+			dst = append(dst, x.Tags[k]...)
+		}
+	}
+	dst = append(dst, '\n')
+	return dst
+}
@@ -0,0 +1,51 @@
+package generated
+
+/**
+* Has the types used to test the generated serializers.
+* @author rnojiri
+**/
+
+//go:generate go run ../../cmd/serializergen
+
+// Host - a named string type
+type Host string
+
+// SimpleJSON - a struct with basic types only
+//
+//serializer:generate
+type SimpleJSON struct {
+	Text    string  `json:"text"`
+	Integer int     `json:"integer"`
+	Float   float64 `json:"float"`
+	Boolean bool    `json:"boolean"`
+}
+
+// CollectionJSON - a struct with a map and an array
+type CollectionJSON struct {
+	Mapping map[string]int `json:"mapping"`
+	Array   []float64      `json:"array"`
+}
+
+// ComplexTypeJSON - a struct with a sub object, an embedded struct and some unmapped fields
+//
+//serializer:generate
+type ComplexTypeJSON struct {
+	Simple SimpleJSON `json:"simple"`
+	CollectionJSON
+	Unsigned uint16  `json:"unsigned"`
+	Small    float32 `json:"small"`
+	Host     Host    `json:"host"`
+	Ignored  string
+}
+
+// MetricPoint - a struct with both json and opentsdb tags
+//
+//serializer:generate
+type MetricPoint struct {
+	Metric    string            `json:"metric" opentsdb:"metric"`
+	Timestamp int64             `json:"timestamp" opentsdb:"timestamp"`
+	Value     float64           `json:"value" opentsdb:"value"`
+	Host      Host              `json:"host" opentsdb:"host"`
+	TTL       int               `json:"ttl" opentsdb:"ttl"`
+	Tags      map[string]string `json:"tags" opentsdb:"tags"`
+}
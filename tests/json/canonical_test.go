@@ -0,0 +1,182 @@
+package json
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	serializer "github.com/uol/serializer/json"
+)
+
+/**
+* Has unit tests for the canonical output (RFC 8785), using the RFC examples.
+* @author rnojiri
+**/
+
+type CanonicalJSON struct {
+	Numbers  []float64         `json:"numbers"`
+	String   string            `json:"string"`
+	Literals []interface{}     `json:"literals"`
+	Labels   map[string]string `json:"labels"`
+	Counter  int64             `json:"counter"`
+}
+
+type CanonicalNumberJSON struct {
+	Number float64 `json:"n"`
+}
+
+// createCanonicalSerializer - creates a serializer in canonical mode
+func createCanonicalSerializer() *serializer.Serializer {
+
+	return serializer.NewWithOptions(serializer.WithCanonical())
+}
+
+// TestCanonicalNumbers - test the number serialization samples of RFC 8785 (appendix B)
+func TestCanonicalNumbers(t *testing.T) {
+
+	s := createCanonicalSerializer()
+	addType(t, s, "n", CanonicalNumberJSON{}, "n")
+
+	samples := map[uint64]string{
+		0x0000000000000000: "0",
+		0x8000000000000000: "0",
+		0x0000000000000001: "5e-324",
+		0x8000000000000001: "-5e-324",
+		0x7fefffffffffffff: "1.7976931348623157e+308",
+		0xffefffffffffffff: "-1.7976931348623157e+308",
+		0x4340000000000000: "9007199254740992",
+		0xc340000000000000: "-9007199254740992",
+		0x4430000000000000: "295147905179352830000",
+		0x44b52d02c7e14af5: "9.999999999999997e+22",
+		0x44b52d02c7e14af6: "1e+23",
+		0x44b52d02c7e14af7: "1.0000000000000001e+23",
+		0x444b1ae4d6e2ef4e: "999999999999999700000",
+		0x444b1ae4d6e2ef4f: "999999999999999900000",
+		0x444b1ae4d6e2ef50: "1e+21",
+		0x3eb0c6f7a0b5ed8c: "9.999999999999997e-7",
+		0x3eb0c6f7a0b5ed8d: "0.000001",
+		0x41b3de4355555553: "333333333.3333332",
+		0x41b3de4355555554: "333333333.33333325",
+		0x41b3de4355555555: "333333333.3333333",
+		0x41b3de4355555556: "333333333.3333334",
+		0x41b3de4355555557: "333333333.33333343",
+		0xbecbf647612f3696: "-0.0000033333333333333333",
+		0x43143ff3c1cb0959: "1424953923781206.2",
+	}
+
+	for bits, expected := range samples {
+		value := math.Float64frombits(bits)
+		assert.Equal(t, `{"n":`+expected+`}`, serialize(t, s, "n", "n", value), "unexpected canonical number of %x", bits)
+	}
+
+	_, err := s.Serialize("n", "n", math.NaN())
+	assert.Error(t, err, "expected an error with NaN")
+
+	_, err = s.Serialize("n", "n", math.Inf(1))
+	assert.Error(t, err, "expected an error with infinity")
+}
+
+// TestCanonicalRFCExample - test the complete example of RFC 8785 (section 3.2.2), as raw and mapped values
+func TestCanonicalRFCExample(t *testing.T) {
+
+	s := createCanonicalSerializer()
+
+	expected := `{"literals":[null,true,false],"numbers":[333333333.3333333,1e+30,4.5,0.002,1e-27],"string":"€$\u000f\nA'B\"\\\\\"/"}`
+
+	addType(t, s, "raw", BytesJSON{}, "fragment")
+
+	raw := serializer.Raw(`{
+		"numbers": [333333333.33333329, 1E30, 4.50, 2e-3, 0.000000000000000000000000001],
+		"string": "\u20ac$\u000F\u000aA'\u0042\u0022\u005c\\\"\/",
+		"literals": [null, true, false]
+	}`)
+
+	result := serialize(t, s, "raw", "fragment", raw)
+	assert.Contains(t, result, `"fragment":`+expected, "expected the canonical raw fragment")
+
+	item := CanonicalJSON{
+		Numbers:  []float64{333333333.33333329, 1e30, 4.50, 2e-3, 0.000000000000000000000000001},
+		String:   "€$\x0f\nA'B\"\\\\\"/",
+		Literals: []interface{}{nil, true, false},
+		Labels:   map[string]string{},
+	}
+
+	addType(t, s, "mapped", item)
+	assert.Equal(t, `{"counter":0,"labels":{},`+expected[1:], serialize(t, s, "mapped"), "expected the canonical mapping")
+}
+
+// TestCanonicalSorting - test the properties sorting by UTF-16 code units (RFC 8785 section 3.2.3)
+func TestCanonicalSorting(t *testing.T) {
+
+	s := createCanonicalSerializer()
+
+	labels := map[string]string{
+		"€":      "Euro Sign",
+		"\r":     "Carriage Return",
+		"\ufb33": "Hebrew Letter Dalet With Dagesh",
+		"1":      "One",
+		"😀":      "Emoji: Grinning Face",
+		"\u0080": "Control",
+		"ö":      "Latin Small Letter O With Diaeresis",
+	}
+
+	expected := `{"counter":0,"labels":{"\r":"Carriage Return","1":"One","` + "\u0080" + `":"Control","ö":"Latin Small Letter O With Diaeresis","€":"Euro Sign","😀":"Emoji: Grinning Face","` + "\ufb33" + `":"Hebrew Letter Dalet With Dagesh"},"literals":[],"numbers":[],"string":""}`
+
+	addType(t, s, "constant", CanonicalJSON{Labels: labels})
+	assert.Equal(t, expected, serialize(t, s, "constant"), "expected the sorted constant keys")
+
+	addType(t, s, "variable", CanonicalJSON{Labels: map[string]string{}}, "labels")
+	assert.Equal(t, expected, serialize(t, s, "variable", "labels", labels), "expected the sorted variable keys")
+}
+
+// TestCanonicalIntegers - test if the integers not represented by a double are rounded like any other number
+func TestCanonicalIntegers(t *testing.T) {
+
+	s := createCanonicalSerializer()
+	addType(t, s, "s", CanonicalJSON{}, "counter")
+
+	expected := `{"counter":%s,"labels":{},"literals":[],"numbers":[],"string":""}`
+
+	assert.Equal(t, fmt.Sprintf(expected, "9007199254740991"), serialize(t, s, "s", "counter", int64(9007199254740991)), "expected the exact integer")
+	assert.Equal(t, fmt.Sprintf(expected, "9223372036854776000"), serialize(t, s, "s", "counter", int64(math.MaxInt64)), "expected the rounded integer")
+	assert.Equal(t, fmt.Sprintf(expected, "-9223372036854776000"), serialize(t, s, "s", "counter", int64(math.MinInt64)), "expected the rounded integer")
+}
+
+// TestDigest - test if the digest is the SHA-256 of the canonical output
+func TestDigest(t *testing.T) {
+
+	s := createCanonicalSerializer()
+	addType(t, s, "s", CanonicalJSON{}, "labels", "numbers")
+
+	first := map[string]string{}
+	second := map[string]string{}
+
+	for _, key := range []string{"a", "b", "c", "d", "e", "f"} {
+		first[key] = key
+	}
+
+	for _, key := range []string{"f", "e", "d", "c", "b", "a"} {
+		second[key] = key
+	}
+
+	digest, err := s.Digest("s", "labels", first, "numbers", []float64{1.50, 2e-3})
+	if !assert.NoError(t, err, "error calculating the digest") {
+		return
+	}
+
+	assert.Equal(t, sha256.Sum256([]byte(serialize(t, s, "s", "labels", first, "numbers", []float64{1.5, 0.002}))), digest, "expected the digest of the output")
+
+	for i := 0; i < 10; i++ {
+
+		other, err := s.Digest("s", "labels", second, "numbers", []float64{1.5, 0.002})
+		if assert.NoError(t, err, "error calculating the digest") {
+			assert.Equal(t, digest, other, "expected the same digest")
+		}
+	}
+
+	_, err = s.Digest("unknown")
+	assert.Error(t, err, "expected an error with an unknown mapping")
+}
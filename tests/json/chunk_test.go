@@ -0,0 +1,82 @@
+package json
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+/**
+* Has unit tests for the size bounded JSON array serialization.
+* @author rnojiri
+**/
+
+// TestSerializeArrayChunked - test if the chunks are valid arrays under the limit
+func TestSerializeArrayChunked(t *testing.T) {
+
+	s, items := createEncoderItems(t, 10)
+
+	const maxBytes = 200
+
+	chunks, err := s.SerializeArrayChunked(maxBytes, items...)
+	if !assert.NoError(t, err, "error serializing the chunks") {
+		return
+	}
+
+	if !assert.True(t, len(chunks) > 1, "expected more than one chunk") {
+		return
+	}
+
+	expectedIndex := 0
+
+	for _, chunk := range chunks {
+
+		assert.True(t, len(chunk.Payload) <= maxBytes, "expected a chunk with at most %d bytes: %d", maxBytes, len(chunk.Payload))
+
+		expected, err := s.SerializeArray(items[chunk.Indexes[0] : chunk.Indexes[len(chunk.Indexes)-1]+1]...)
+		if !assert.NoError(t, err, "error serializing the array") {
+			return
+		}
+
+		assert.Equal(t, expected, chunk.Payload, "expected a valid array with the chunk items")
+
+		for _, index := range chunk.Indexes {
+			assert.Equal(t, expectedIndex, index, "expected the indexes in order")
+			expectedIndex++
+		}
+
+		actual := []SimpleJSON{}
+		expectedItems := make([]SimpleJSON, len(chunk.Indexes))
+		for i, index := range chunk.Indexes {
+			expectedItems[i] = SimpleJSON{Text: "encoder", Integer: index}
+		}
+
+		validateJSON(t, chunk.Payload, &expectedItems, &actual)
+	}
+
+	assert.Equal(t, len(items), expectedIndex, "expected all items in the chunks")
+}
+
+// TestSerializeArrayChunkedErrors - test an item exceeding the limit and an empty array
+func TestSerializeArrayChunkedErrors(t *testing.T) {
+
+	s, items := createEncoderItems(t, 3)
+
+	_, err := s.SerializeArrayChunked(10, items...)
+	assert.Error(t, err, "expected an error with an item exceeding the limit")
+
+	chunks, err := s.SerializeArrayChunked(10)
+	assert.NoError(t, err, "expected no error without items")
+	assert.Empty(t, chunks, "expected no chunks")
+}
+
+// TestSerializeArrayChunkedPanic - test if an unexpected panic is returned as an error instead of chunking an empty item
+func TestSerializeArrayChunkedPanic(t *testing.T) {
+
+	s, items := createEncoderItems(t, 3)
+	fuse := addPanicItem(t, s)
+
+	chunks, err := s.SerializeArrayChunked(1024, items[0], fuse, items[1])
+	assert.Error(t, err, "expected the panic as an error")
+	assert.Nil(t, chunks, "expected no chunks")
+}
@@ -0,0 +1,342 @@
+//go:build go1.18
+// +build go1.18
+
+package json
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"math/rand"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	serializer "github.com/uol/serializer/json"
+)
+
+/**
+* Has the differential fuzz tests of the compat mode against encoding/json, using random struct types and values
+* (testing.F requires Go 1.18, the omitzero tags are generated only from Go 1.24, see compat_omitzero_test.go).
+* @author rnojiri
+**/
+
+// fuzzOmitZero - generates omitzero tags, encoding/json supports them only from Go 1.24
+var fuzzOmitZero bool
+
+const (
+	numFuzzSeeds     int = 300
+	maxRandomDepth   int = 3
+	numVariableField int = 3
+)
+
+var (
+	randomStrings = []string{
+		"", "text", "<b>&amp;</b>", "line\nbreak\ttab\r", "\x00\x01\x1f\x7f", `quote " and \ bar`,
+		"  ", "invalid \xff\xfe utf8", "100% %d %s %%", "émoji 😀", "\b\f",
+	}
+
+	randomFloats = []float64{
+		0, 1, -1, 0.1, 1.5, -2.75, 1e20, 1e21, 1e-6, 1e-7, 123456789.123456789, -0.000001, 3e38, 5e-324, math.MaxFloat64,
+	}
+
+	randomNumbers = []json.Number{"", "0", "-1", "1.5e10", "12345678901234567890"}
+
+	randomTagNames = []string{"a", "b", "c", "F0", "F1"}
+
+	timeType   = reflect.TypeOf(time.Time{})
+	numberType = reflect.TypeOf(json.Number(""))
+)
+
+// randomGenerator - builds random struct types and values
+type randomGenerator struct {
+	r *rand.Rand
+}
+
+// newRandomGenerator - creates a new generator from the seed
+func newRandomGenerator(seed int64) *randomGenerator {
+
+	return &randomGenerator{
+		r: rand.New(rand.NewSource(seed)),
+	}
+}
+
+// randomType - returns a random type, nested types are limited by the depth
+func (g *randomGenerator) randomType(depth int) reflect.Type {
+
+	n := 18
+	if depth >= maxRandomDepth {
+		n = 11
+	}
+
+	switch g.r.Intn(n) {
+	case 0:
+		return reflect.TypeOf(false)
+	case 1:
+		return reflect.TypeOf(0)
+	case 2:
+		return reflect.TypeOf(int8(0))
+	case 3:
+		return reflect.TypeOf(uint16(0))
+	case 4:
+		return reflect.TypeOf(float32(0))
+	case 5:
+		return reflect.TypeOf(float64(0))
+	case 6:
+		return reflect.TypeOf("")
+	case 7:
+		return reflect.TypeOf((*interface{})(nil)).Elem()
+	case 8:
+		return reflect.TypeOf([]byte{})
+	case 9:
+		return timeType
+	case 10:
+		return numberType
+	case 11:
+		return reflect.PtrTo(g.randomType(depth + 1))
+	case 12:
+		return reflect.SliceOf(g.randomType(depth + 1))
+	case 13:
+		return reflect.ArrayOf(2, g.randomType(depth+1))
+	case 14:
+		return reflect.MapOf(reflect.TypeOf(""), g.randomType(depth+1))
+	case 15:
+		return reflect.MapOf(reflect.TypeOf(int64(0)), g.randomType(depth+1))
+	default:
+		return g.randomStruct(depth+1, 0)
+	}
+}
+
+// randomTag - returns a random json tag, the names are repeated to create conflicts
+func (g *randomGenerator) randomTag() reflect.StructTag {
+
+	name := randomTagNames[g.r.Intn(len(randomTagNames))]
+
+	switch g.r.Intn(8) {
+	case 0:
+		return ""
+	case 1:
+		return `json:"-"`
+	case 2:
+		return reflect.StructTag(fmt.Sprintf(`json:"%s,omitempty"`, name))
+	case 3:
+		return `json:",omitempty"`
+	case 4:
+		if !fuzzOmitZero {
+			return reflect.StructTag(fmt.Sprintf(`json:"%s,omitempty"`, name))
+		}
+		return reflect.StructTag(fmt.Sprintf(`json:"%s,omitzero"`, name))
+	case 5:
+		return reflect.StructTag(fmt.Sprintf(`json:"%s,string"`, name))
+	default:
+		return reflect.StructTag(fmt.Sprintf(`json:"%s"`, name))
+	}
+}
+
+// randomStruct - returns a random struct type, with the variable fields (named v0, v1...) at the end
+func (g *randomGenerator) randomStruct(depth, numVariables int) reflect.Type {
+
+	numFields := 1 + g.r.Intn(5)
+
+	fields := make([]reflect.StructField, 0, numFields+numVariables)
+
+	for i := 0; i < numFields; i++ {
+		fields = append(fields, reflect.StructField{
+			Name: fmt.Sprintf("F%d", i),
+			Type: g.randomType(depth),
+			Tag:  g.randomTag(),
+		})
+	}
+
+	for i := 0; i < numVariables; i++ {
+
+		t := g.randomType(depth)
+		for t.Kind() == reflect.Struct && t != timeType {
+			t = g.randomType(depth)
+		}
+
+		fields = append(fields, reflect.StructField{
+			Name: fmt.Sprintf("V%d", i),
+			Type: t,
+			Tag:  reflect.StructTag(fmt.Sprintf(`json:"v%d"`, i)),
+		})
+	}
+
+	return reflect.StructOf(fields)
+}
+
+// randomInterface - returns a random value for an interface{}
+func (g *randomGenerator) randomInterface(depth int) interface{} {
+
+	switch g.r.Intn(7) {
+	case 0:
+		return nil
+	case 1:
+		return g.r.Intn(1000) - 500
+	case 2:
+		return randomStrings[g.r.Intn(len(randomStrings))]
+	case 3:
+		return randomFloats[g.r.Intn(len(randomFloats))]
+	case 4:
+		return g.r.Intn(2) == 0
+	case 5:
+		if depth >= maxRandomDepth {
+			return nil
+		}
+		return []interface{}{g.randomInterface(depth + 1), g.randomInterface(depth + 1)}
+	default:
+		if depth >= maxRandomDepth {
+			return nil
+		}
+		return map[string]interface{}{
+			randomStrings[g.r.Intn(len(randomStrings))]: g.randomInterface(depth + 1),
+		}
+	}
+}
+
+// randomValue - returns a random value of the type
+func (g *randomGenerator) randomValue(t reflect.Type, depth int) reflect.Value {
+
+	v := reflect.New(t).Elem()
+
+	if t == timeType {
+		v.Set(reflect.ValueOf(time.Unix(g.r.Int63n(4e9), g.r.Int63n(1e9)).In(time.FixedZone("z", g.r.Intn(3)*3600))))
+		return v
+	}
+
+	if t == numberType {
+		v.SetString(string(randomNumbers[g.r.Intn(len(randomNumbers))]))
+		return v
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		v.SetBool(g.r.Intn(2) == 0)
+	case reflect.Int, reflect.Int8, reflect.Int64:
+		v.SetInt(int64(g.r.Intn(256) - 128))
+	case reflect.Uint16:
+		v.SetUint(uint64(g.r.Intn(math.MaxUint16)))
+	case reflect.Float32:
+		f := randomFloats[g.r.Intn(len(randomFloats))]
+		if math.Abs(f) > math.MaxFloat32 {
+			f = 1
+		}
+		v.SetFloat(f)
+	case reflect.Float64:
+		v.SetFloat(randomFloats[g.r.Intn(len(randomFloats))])
+	case reflect.String:
+		v.SetString(randomStrings[g.r.Intn(len(randomStrings))])
+	case reflect.Interface:
+		if value := g.randomInterface(depth); value != nil {
+			v.Set(reflect.ValueOf(value))
+		}
+	case reflect.Ptr:
+		if g.r.Intn(3) > 0 {
+			p := reflect.New(t.Elem())
+			p.Elem().Set(g.randomValue(t.Elem(), depth+1))
+			v.Set(p)
+		}
+	case reflect.Slice:
+		if g.r.Intn(4) > 0 {
+			n := g.r.Intn(3)
+			v.Set(reflect.MakeSlice(t, n, n))
+			for i := 0; i < n; i++ {
+				v.Index(i).Set(g.randomValue(t.Elem(), depth+1))
+			}
+		}
+	case reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			v.Index(i).Set(g.randomValue(t.Elem(), depth+1))
+		}
+	case reflect.Map:
+		if g.r.Intn(4) > 0 {
+			v.Set(reflect.MakeMap(t))
+			for i := g.r.Intn(3); i > 0; i-- {
+				v.SetMapIndex(g.randomValue(t.Key(), depth+1), g.randomValue(t.Elem(), depth+1))
+			}
+		}
+	case reflect.Struct:
+		for i := 0; i < v.NumField(); i++ {
+			v.Field(i).Set(g.randomValue(t.Field(i).Type, depth+1))
+		}
+	}
+
+	return v
+}
+
+// FuzzCompat - compares the compat mode outputs (marshalled, constants and variables) with encoding/json
+func FuzzCompat(f *testing.F) {
+
+	for i := 0; i < numFuzzSeeds; i++ {
+		f.Add(int64(i))
+	}
+
+	f.Fuzz(func(t *testing.T, seed int64) {
+
+		g := newRandomGenerator(seed)
+
+		typ := g.randomStruct(0, numVariableField)
+		item := g.randomValue(typ, 0)
+
+		expected, stdErr := json.Marshal(item.Interface())
+
+		s := serializer.NewWithOptions(serializer.WithCompat())
+
+		result, err := s.Marshal(item.Interface())
+		if stdErr != nil {
+			assert.Error(t, err, "expected an error like encoding/json (%s)", stdErr)
+			return
+		}
+
+		if !assert.NoError(t, err, "error marshalling type %s", typ) {
+			return
+		}
+
+		assert.Equal(t, string(expected), result, "expected the marshalled output of encoding/json with type %s", typ)
+
+		err = s.Add("constants", item.Interface())
+		if !assert.NoError(t, err, "error adding the constants mapping of type %s", typ) {
+			return
+		}
+
+		result, err = s.Serialize("constants")
+		if assert.NoError(t, err, "error serializing the constants of type %s", typ) {
+			assert.Equal(t, string(expected), result, "expected the constants output of encoding/json with type %s", typ)
+		}
+
+		paths := make([]string, numVariableField)
+		params := make([]interface{}, 0, numVariableField*2)
+
+		other := g.randomValue(typ, 0)
+		merged := reflect.New(typ).Elem()
+		merged.Set(item)
+
+		for i := 0; i < numVariableField; i++ {
+
+			field := typ.NumField() - numVariableField + i
+			paths[i] = fmt.Sprintf("v%d", i)
+
+			params = append(params, paths[i], other.Field(field).Interface())
+			merged.Field(field).Set(other.Field(field))
+		}
+
+		err = s.Add("variables", item.Interface(), paths...)
+		if !assert.NoError(t, err, "error adding the variables mapping of type %s", typ) {
+			return
+		}
+
+		expected, stdErr = json.Marshal(merged.Interface())
+
+		result, err = s.Serialize("variables", params...)
+		if stdErr != nil {
+			assert.Error(t, err, "expected an error like encoding/json (%s)", stdErr)
+			return
+		}
+
+		if assert.NoError(t, err, "error serializing the variables of type %s", typ) {
+			assert.Equal(t, string(expected), result, "expected the variables output of encoding/json with type %s", typ)
+		}
+	})
+}
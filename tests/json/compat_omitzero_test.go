@@ -0,0 +1,40 @@
+//go:build go1.24
+// +build go1.24
+
+package json
+
+import (
+	"testing"
+	"time"
+)
+
+/**
+* Has unit tests for the omitzero option of the compatibility mode, encoding/json supports it only from Go 1.24.
+* @author rnojiri
+**/
+
+type CompatOmitZeroJSON struct {
+	Time    time.Time      `json:"time,omitzero"`
+	Integer int            `json:"integer,omitzero"`
+	Pointer *int           `json:"pointer,omitzero"`
+	Mapping map[string]int `json:"mapping,omitzero"`
+	Simple  SimpleJSON     `json:"simple,omitzero"`
+	Value   int            `json:"value"`
+}
+
+// init - enables the omitzero tags in the differential fuzz test
+func init() {
+	fuzzOmitZero = true
+}
+
+// TestCompatOmitZero - test the omitzero option, including the types implementing the IsZero method
+func TestCompatOmitZero(t *testing.T) {
+
+	s := createCompatSerializer()
+
+	zero := 0
+
+	assertCompat(t, s, CompatOmitZeroJSON{})
+	assertCompat(t, s, CompatOmitZeroJSON{Time: time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC), Integer: 1, Pointer: &zero, Mapping: map[string]int{}, Simple: SimpleJSON{Text: "simple"}, Value: 1})
+	assertCompat(t, s, CompatOmitZeroJSON{Time: time.Date(1, 1, 1, 0, 0, 0, 0, time.FixedZone("zone", 3600))})
+}
@@ -0,0 +1,194 @@
+package json
+
+import (
+	"encoding/json"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	serializer "github.com/uol/serializer/json"
+	common "github.com/uol/serializer/serializer"
+)
+
+/**
+* Has unit tests for the encoding/json compatibility mode.
+* @author rnojiri
+**/
+
+type CompatScalarsJSON struct {
+	Text     string  `json:"text"`
+	Float64  float64 `json:"float64"`
+	Float32  float32 `json:"float32"`
+	Integer  int8    `json:"integer"`
+	Untagged string
+	Ignored  string `json:"-"`
+}
+
+type CompatNilJSON struct {
+	Mapping map[string]int `json:"mapping"`
+	Array   []string       `json:"array"`
+	Pointer *int           `json:"pointer"`
+	Any     interface{}    `json:"any"`
+	Bytes   []byte         `json:"bytes"`
+}
+
+type CompatTagOptionsJSON struct {
+	Empty     string            `json:"empty,omitempty"`
+	NotEmpty  string            `json:"notEmpty,omitempty"`
+	Mapping   map[string]string `json:"mapping,omitempty"`
+	Quoted    int               `json:"quoted,string"`
+	QuotedStr string            `json:"quotedStr,string"`
+	QuotedPtr *float64          `json:"quotedPtr,string"`
+	Value     int               `json:"value"`
+}
+
+type CompatMarshalersJSON struct {
+	Time    time.Time            `json:"time"`
+	Number  json.Number          `json:"number"`
+	Address net.IP               `json:"address"`
+	Level   Level                `json:"level"`
+	Raw     json.RawMessage      `json:"raw"`
+	ByLevel map[Level]time.Month `json:"byLevel"`
+}
+
+// createCompatSerializer - creates a serializer in compat mode
+func createCompatSerializer() *serializer.Serializer {
+
+	return serializer.NewWithOptions(serializer.WithCompat())
+}
+
+// assertCompat - asserts the constant mapping and the marshalled output are equal to encoding/json ones
+func assertCompat(t *testing.T, s *serializer.Serializer, item interface{}) bool {
+
+	expected := stdMarshal(t, item)
+
+	if !assert.NoError(t, s.Add("compat", item), "error adding the mapping") {
+		return false
+	}
+
+	ok := assert.Equal(t, expected, serialize(t, s, "compat"), "expected the same output of encoding/json")
+
+	result, err := s.Marshal(item)
+	if !assert.NoError(t, err, "error marshalling") {
+		return false
+	}
+
+	return assert.Equal(t, expected, result, "expected the same marshalled output of encoding/json") && ok
+}
+
+// TestCompatScalars - test the escaping and the float format of the compat mode
+func TestCompatScalars(t *testing.T) {
+
+	s := createCompatSerializer()
+
+	texts := []string{"<a&b>", "line\nbreak\ttab\r", "\x00\x1f\b\f", "  ", "invalid \xff utf8", `"quoted" \ bar`, "100% %d", "émoji 😀"}
+	floats := []float64{0, 1, -1.5, 0.1, 1e20, 1e21, 1e-6, 1e-7, 123456789.125, 5e-324}
+
+	for i, text := range texts {
+
+		item := CompatScalarsJSON{
+			Text:     text,
+			Float64:  floats[i],
+			Float32:  float32(floats[len(floats)-1-i]),
+			Integer:  int8(-i),
+			Untagged: text,
+			Ignored:  text,
+		}
+
+		assertCompat(t, s, item)
+	}
+
+	addType(t, s, "variables", CompatScalarsJSON{}, "text", "float64", "float32")
+
+	result := serialize(t, s, "variables", "text", "<\n>", "float64", 1e-7, "float32", float32(0.1))
+	assert.Equal(t, stdMarshal(t, CompatScalarsJSON{Text: "<\n>", Float64: 1e-7, Float32: 0.1}), result, "expected the same variables of encoding/json")
+}
+
+// TestCompatNil - test if the nil values are written as null
+func TestCompatNil(t *testing.T) {
+
+	s := createCompatSerializer()
+
+	assertCompat(t, s, CompatNilJSON{})
+
+	one := 1
+	item := CompatNilJSON{Mapping: map[string]int{"b": 2, "a": 1}, Array: []string{}, Pointer: &one, Any: []int{1}, Bytes: []byte("bytes")}
+	assertCompat(t, s, item)
+
+	addType(t, s, "variables", item, "mapping", "array", "pointer", "any", "bytes")
+
+	result := serialize(t, s, "variables", "mapping", nil, "array", []string(nil), "pointer", nil, "any", nil, "bytes", nil)
+	assert.Equal(t, stdMarshal(t, CompatNilJSON{}), result, "expected null variables")
+
+	assert.Equal(t, stdMarshal(t, item), serialize(t, s, "variables"), "expected the default values")
+}
+
+// TestCompatTagOptions - test the omitempty and string options (omitzero is tested in compat_omitzero_test.go)
+func TestCompatTagOptions(t *testing.T) {
+
+	s := createCompatSerializer()
+
+	ratio := 0.25
+
+	assertCompat(t, s, CompatTagOptionsJSON{})
+	assertCompat(t, s, CompatTagOptionsJSON{
+		NotEmpty:  "value",
+		Mapping:   map[string]string{"a": "<b>"},
+		Quoted:    10,
+		QuotedStr: `a "text"`,
+		QuotedPtr: &ratio,
+		Value:     1,
+	})
+
+	addType(t, s, "quoted", CompatTagOptionsJSON{}, "quoted", "quotedStr", "value")
+
+	result := serialize(t, s, "quoted", "quoted", 5, "quotedStr", "<x>", "value", 2)
+	assert.Equal(t, stdMarshal(t, CompatTagOptionsJSON{Quoted: 5, QuotedStr: "<x>", Value: 2}), result, "expected the quoted variables")
+
+	err := s.Add("omitted", CompatTagOptionsJSON{}, "notEmpty")
+	assert.Error(t, err, "expected an error with a variable which may be omitted")
+
+	err = s.Add("omittedInside", CompatTagOptionsJSON{Mapping: map[string]string{"a": "b"}}, "mapping.a")
+	assert.Error(t, err, "expected an error with a variable inside a property which may be omitted")
+}
+
+// TestCompatMarshalers - test if the json.Marshaler and encoding.TextMarshaler implementations are used
+func TestCompatMarshalers(t *testing.T) {
+
+	s := createCompatSerializer()
+
+	assertCompat(t, s, CompatMarshalersJSON{})
+	assertCompat(t, s, CompatMarshalersJSON{
+		Time:    time.Date(2021, 5, 6, 7, 8, 9, 10, time.FixedZone("x", -3600)),
+		Number:  "12.5e3",
+		Address: net.ParseIP("::1"),
+		Level:   1,
+		Raw:     json.RawMessage(`{ "spaced" : [ 1, "<tag>" ] }`),
+		ByLevel: map[Level]time.Month{0: time.January, 1: time.December},
+	})
+
+	_, err := s.Marshal(CompatMarshalersJSON{Level: 3})
+	assert.Error(t, err, "expected the MarshalText error")
+}
+
+// TestCompatOverrides - test if the options which could change the output are overridden
+func TestCompatOverrides(t *testing.T) {
+
+	s := serializer.NewWithOptions(
+		serializer.WithNamingStrategy(serializer.SnakeCase),
+		serializer.WithFloatFormat('e', 2),
+		serializer.WithNullPolicy(common.NullError),
+		serializer.WithCompat(),
+	)
+
+	item := CompatScalarsJSON{Text: "text", Float64: 1.5, Untagged: "untagged"}
+	assertCompat(t, s, item)
+
+	addType(t, s, "s", item, "text")
+	assert.Equal(t, stdMarshal(t, CompatScalarsJSON{Float64: 1.5, Untagged: "untagged"}), serialize(t, s, "s", "text", ""), "expected the same output of encoding/json")
+
+	_, err := s.Serialize("s", "text", nil)
+	assert.NoError(t, err, "expected null values to be written")
+}
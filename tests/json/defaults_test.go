@@ -0,0 +1,98 @@
+package json
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	gotest "github.com/uol/gotest/utils"
+	serializer "github.com/uol/serializer/json"
+)
+
+/**
+* Has unit tests for the variables default values.
+* @author rnojiri
+**/
+
+// TestDefaultValues - test serializing only a subset of the variables
+func TestDefaultValues(t *testing.T) {
+
+	newType := SimpleJSON{
+		Boolean: true,
+		Float:   float64(gotest.RandomInt(0, 100)),
+		Integer: gotest.RandomInt(0, 1000),
+		Text:    "default",
+	}
+
+	s := createSerializer()
+	addType(t, s, "s", newType, "boolean", "float", "integer", "text")
+
+	result := serialize(t, s, "s")
+
+	actual := SimpleJSON{}
+	validateJSON(t, result, &newType, &actual)
+
+	result = serialize(t, s, "s", "text", "changed")
+
+	expected := newType
+	expected.Text = "changed"
+
+	actual = SimpleJSON{}
+	validateJSON(t, result, &expected, &actual)
+}
+
+// TestDefaultCollections - test the default values of map and array variables
+func TestDefaultCollections(t *testing.T) {
+
+	newType := ComplexTypeJSON{
+		Simple: SimpleJSON{Text: "complex"},
+		CollectionJSON: CollectionJSON{
+			Mapping: map[string]int{"1": 1, "2": 2},
+			Array:   []float64{1.0, 2.0},
+		},
+	}
+
+	s := createSerializer()
+	addType(t, s, "s", newType, "mapping", "array", "simple.text")
+
+	result := serialize(t, s, "s", "array", []float64{3.0})
+
+	expected := newType
+	expected.Array = []float64{3.0}
+
+	actual := ComplexTypeJSON{}
+	validateJSON(t, result, &expected, &actual)
+}
+
+// TestRequiredVariables - test the required variables validation
+func TestRequiredVariables(t *testing.T) {
+
+	newType := SimpleJSON{
+		Text:    "required",
+		Integer: 1,
+	}
+
+	s := createSerializer()
+
+	err := s.AddWithOptions("s", newType,
+		serializer.Variables("integer", "float"),
+		serializer.Required("text"),
+	)
+	if !assert.NoError(t, err, "error adding the mapping") {
+		return
+	}
+
+	_, err = s.Serialize("s", "integer", 2)
+	assert.Error(t, err, "expected an error without the required variable")
+
+	result := serialize(t, s, "s", "text", "supplied")
+
+	expected := newType
+	expected.Text = "supplied"
+
+	actual := SimpleJSON{}
+	validateJSON(t, result, &expected, &actual)
+
+	err = s.AddWithOptions("invalid", newType, serializer.Required("unknown"))
+	assert.Error(t, err, "expected an error with an unknown required variable")
+}
@@ -0,0 +1,150 @@
+package json
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	serializer "github.com/uol/serializer/json"
+)
+
+/**
+* Has unit tests for the embedded structs, compared with the standard library.
+* @author rnojiri
+**/
+
+type EmbeddedBase struct {
+	ID   int `json:"id"`
+	Name string
+}
+
+type EmbeddedAudit struct {
+	Name      string
+	CreatedBy string `json:"createdBy"`
+	Owner     string
+}
+
+type EmbeddedOwner struct {
+	Owner string `json:"Owner"`
+	Team  string `json:"team"`
+}
+
+type embeddedSecret struct {
+	Level int `json:"level"`
+}
+
+type EmbeddedLabel string
+
+type EmbeddedEntity struct {
+	EmbeddedBase
+	EmbeddedAudit
+	*EmbeddedOwner
+	embeddedSecret
+	EmbeddedLabel
+	ID string `json:"id"`
+}
+
+// createEmbeddingSerializer - creates a serializer writing the same output of the standard library
+func createEmbeddingSerializer() *serializer.Serializer {
+
+	return serializer.NewWithOptions(
+		serializer.WithNamingStrategy(serializer.FieldName),
+		serializer.WithSortedKeys(),
+		serializer.WithHTMLEscape(),
+	)
+}
+
+// createEmbeddedEntity - creates an entity with all kinds of embedded fields
+func createEmbeddedEntity(owner *EmbeddedOwner) EmbeddedEntity {
+
+	return EmbeddedEntity{
+		EmbeddedBase:   EmbeddedBase{ID: 1, Name: "base"},
+		EmbeddedAudit:  EmbeddedAudit{Name: "audit", CreatedBy: "admin", Owner: "untagged"},
+		EmbeddedOwner:  owner,
+		embeddedSecret: embeddedSecret{Level: 3},
+		EmbeddedLabel:  "label",
+		ID:             "entity",
+	}
+}
+
+// TestEmbeddingConstants - test if the embedded fields are promoted like the standard library does
+func TestEmbeddingConstants(t *testing.T) {
+
+	s := createEmbeddingSerializer()
+
+	entities := map[string]EmbeddedEntity{
+		"owner":   createEmbeddedEntity(&EmbeddedOwner{Owner: "tagged", Team: "core"}),
+		"noOwner": createEmbeddedEntity(nil),
+	}
+
+	for name, entity := range entities {
+		addType(t, s, name, entity)
+		assert.Equal(t, stdMarshal(t, entity), serialize(t, s, name), "expected the same output of the standard library")
+	}
+
+	expected := `{"createdBy":"admin","Owner":"tagged","team":"core","level":3,"EmbeddedLabel":"label","id":"entity"}`
+
+	s = serializer.NewWithOptions(serializer.WithNamingStrategy(serializer.FieldName))
+	addType(t, s, "owner", entities["owner"])
+	assert.Equal(t, expected, serialize(t, s, "owner"), "expected the fields in declaration order")
+}
+
+// TestEmbeddingVariables - test if the promoted fields can be variables
+func TestEmbeddingVariables(t *testing.T) {
+
+	s := createEmbeddingSerializer()
+
+	entity := createEmbeddedEntity(&EmbeddedOwner{Owner: "tagged", Team: "core"})
+	addType(t, s, "s", entity, "id", "createdBy", "team", "level", "EmbeddedLabel")
+
+	result := serialize(t, s, "s", "id", "other", "createdBy", "root", "team", "infra", "level", 5, "EmbeddedLabel", EmbeddedLabel("new"))
+
+	entity.ID = "other"
+	entity.CreatedBy = "root"
+	entity.Team = "infra"
+	entity.Level = 5
+	entity.EmbeddedLabel = "new"
+
+	assert.Equal(t, stdMarshal(t, entity), result, "expected the same output of the standard library")
+
+	_, err := s.Serialize("s", "Name", "conflict")
+	assert.Error(t, err, "expected the conflicting names to be dropped")
+}
+
+// TestEmbeddingMarshal - test if the marshalled embedded pointers are written only when not nil
+func TestEmbeddingMarshal(t *testing.T) {
+
+	s := createEmbeddingSerializer()
+
+	for _, owner := range []*EmbeddedOwner{nil, {Owner: "tagged", Team: "core"}, nil} {
+
+		entity := createEmbeddedEntity(owner)
+
+		result, err := s.Marshal(entity)
+		if assert.NoError(t, err, "error marshalling") {
+			assert.Equal(t, stdMarshal(t, entity), result, "expected the same output of the standard library")
+		}
+	}
+}
+
+// TestEmbeddingParseInto - test if the nil embedded pointers are allocated when parsing
+func TestEmbeddingParseInto(t *testing.T) {
+
+	s := createEmbeddingSerializer()
+
+	addType(t, s, "s", createEmbeddedEntity(&EmbeddedOwner{}), "id", "team")
+
+	result := serialize(t, s, "s", "id", "parsed", "team", "sre")
+
+	actual := EmbeddedEntity{}
+	err := s.ParseInto("s", []byte(result), &actual)
+	if !assert.NoError(t, err, "error parsing into the struct") {
+		return
+	}
+
+	assert.Equal(t, "parsed", actual.ID, "expected the outer id")
+	assert.Equal(t, 0, actual.EmbeddedBase.ID, "expected the shadowed id untouched")
+	if assert.NotNil(t, actual.EmbeddedOwner, "expected the embedded pointer to be allocated") {
+		assert.Equal(t, "sre", actual.Team, "expected the promoted field")
+	}
+}
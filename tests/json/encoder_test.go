@@ -0,0 +1,212 @@
+package json
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	serializer "github.com/uol/serializer/json"
+)
+
+/**
+* Has unit tests for the incremental JSON array encoder.
+* @author rnojiri
+**/
+
+// recordingWriter - records each write call
+type recordingWriter struct {
+	writes []string
+}
+
+// Write - records the written data
+func (w *recordingWriter) Write(data []byte) (int, error) {
+
+	w.writes = append(w.writes, string(data))
+
+	return len(data), nil
+}
+
+// createEncoderItems - creates the serializer and some items
+func createEncoderItems(t *testing.T, size int) (*serializer.Serializer, []*serializer.ArrayItem) {
+
+	s := createSerializer()
+	addType(t, s, "s", SimpleJSON{Text: "encoder"}, "integer")
+
+	items := make([]*serializer.ArrayItem, size)
+	for i := 0; i < size; i++ {
+		items[i] = &serializer.ArrayItem{Name: "s", Parameters: []interface{}{"integer", i}}
+	}
+
+	return s, items
+}
+
+// Fuse - a value which makes its custom encoder panic when lit
+type Fuse struct {
+	Lit bool
+}
+
+type FuseJSON struct {
+	Fuse Fuse `json:"fuse"`
+}
+
+// addPanicItem - registers the panicking encoder, adds its mapping and returns an item which makes it panic
+func addPanicItem(t *testing.T, s *serializer.Serializer) *serializer.ArrayItem {
+
+	err := s.RegisterEncoder(reflect.TypeOf(Fuse{}), func(dst []byte, v reflect.Value) ([]byte, error) {
+
+		if v.Interface().(Fuse).Lit {
+			panic("lit fuse")
+		}
+
+		return append(dst, `"fuse"`...), nil
+	})
+
+	if !assert.NoError(t, err, "error registering the encoder") {
+		panic(err)
+	}
+
+	addType(t, s, "fuse", FuseJSON{}, "fuse")
+
+	return &serializer.ArrayItem{Name: "fuse", Parameters: []interface{}{"fuse", Fuse{Lit: true}}}
+}
+
+// encode - encodes all items using the array encoder
+func encode(t *testing.T, e *serializer.ArrayEncoder, items []*serializer.ArrayItem) *recordingWriter {
+
+	w := &recordingWriter{}
+
+	if !assert.NoError(t, e.Begin(w), "error beginning the array") {
+		panic("begin")
+	}
+
+	for _, item := range items {
+		if !assert.NoError(t, e.Add(item), "error adding an item") {
+			panic("add")
+		}
+	}
+
+	if !assert.NoError(t, e.End(), "error ending the array") {
+		panic("end")
+	}
+
+	return w
+}
+
+// TestArrayEncoder - test if the encoder output is the same of the array serialization
+func TestArrayEncoder(t *testing.T) {
+
+	s, items := createEncoderItems(t, 10)
+
+	expected, err := s.SerializeArray(items...)
+	if !assert.NoError(t, err, "error serializing the array") {
+		return
+	}
+
+	e := s.NewArrayEncoder(0, 0)
+
+	w := encode(t, e, items)
+	assert.Equal(t, []string{expected}, w.writes, "expected a single write without thresholds")
+
+	w = encode(t, e, nil)
+	assert.Equal(t, []string{"[]"}, w.writes, "expected an empty array")
+}
+
+// TestArrayEncoderPanic - test if an unexpected panic is returned as an error instead of adding an empty item
+func TestArrayEncoderPanic(t *testing.T) {
+
+	s, items := createEncoderItems(t, 2)
+	fuse := addPanicItem(t, s)
+	e := s.NewArrayEncoder(0, 0)
+
+	w := &recordingWriter{}
+	assert.NoError(t, e.Begin(w), "expected no error beginning")
+	assert.NoError(t, e.Add(items[0]), "expected no error adding")
+	assert.Error(t, e.Add(fuse), "expected the panic as an error")
+	assert.NoError(t, e.Add(items[1]), "expected no error adding")
+	assert.NoError(t, e.End(), "expected no error ending")
+
+	expected, err := s.SerializeArray(items...)
+	if assert.NoError(t, err, "error serializing the array") {
+		assert.Equal(t, expected, strings.Join(w.writes, ""), "expected only the valid items")
+	}
+}
+
+// TestArrayEncoderItemThreshold - test the auto flush by number of items
+func TestArrayEncoderItemThreshold(t *testing.T) {
+
+	s, items := createEncoderItems(t, 10)
+
+	expected, err := s.SerializeArray(items...)
+	if !assert.NoError(t, err, "error serializing the array") {
+		return
+	}
+
+	w := encode(t, s.NewArrayEncoder(0, 3), items)
+
+	assert.Len(t, w.writes, 4, "expected 3 automatic flushes and the final one")
+	assert.Equal(t, expected, strings.Join(w.writes, ""), "expected same output")
+}
+
+// TestArrayEncoderByteThreshold - test the auto flush by number of bytes
+func TestArrayEncoderByteThreshold(t *testing.T) {
+
+	s, items := createEncoderItems(t, 10)
+
+	expected, err := s.SerializeArray(items...)
+	if !assert.NoError(t, err, "error serializing the array") {
+		return
+	}
+
+	const maxBytes = 150
+
+	w := encode(t, s.NewArrayEncoder(maxBytes, 0), items)
+
+	assert.True(t, len(w.writes) > 1, "expected more than one write")
+	assert.Equal(t, expected, strings.Join(w.writes, ""), "expected same output")
+
+	for _, write := range w.writes {
+		assert.True(t, len(write) <= maxBytes, "expected writes with at most %d bytes: %d", maxBytes, len(write))
+	}
+}
+
+// TestArrayEncoderErrors - test the encoder errors
+func TestArrayEncoderErrors(t *testing.T) {
+
+	s, items := createEncoderItems(t, 1)
+	e := s.NewArrayEncoder(0, 0)
+
+	assert.Error(t, e.Add(items[0]), "expected an error adding before beginning")
+	assert.Error(t, e.End(), "expected an error ending before beginning")
+
+	w := &recordingWriter{}
+	assert.NoError(t, e.Begin(w), "expected no error beginning")
+	assert.Error(t, e.Begin(w), "expected an error beginning twice")
+	assert.Error(t, e.Add(&serializer.ArrayItem{Name: "unknown"}), "expected an error with an unknown mapping")
+	assert.NoError(t, e.End(), "expected no error ending")
+	assert.Equal(t, []string{"[]"}, w.writes, "expected an empty array")
+}
+
+// TestArrayEncoderClosingBracket - test if the closing bracket is flushed alone when it would exceed the bytes threshold
+func TestArrayEncoderClosingBracket(t *testing.T) {
+
+	type SmallJSON struct {
+		A int `json:"a"`
+	}
+
+	s := createSerializer()
+	addType(t, s, "a", SmallJSON{A: 1})
+
+	items := []*serializer.ArrayItem{{Name: "a"}, {Name: "a"}}
+
+	const maxBytes = 8
+
+	w := encode(t, s.NewArrayEncoder(maxBytes, 0), items)
+
+	assert.Equal(t, []string{`[{"a":1}`, `,{"a":1}`, `]`}, w.writes, "expected the closing bracket in its own write")
+
+	for _, write := range w.writes {
+		assert.True(t, len(write) <= maxBytes, "expected writes with at most %d bytes: %d", maxBytes, len(write))
+	}
+}
@@ -0,0 +1,187 @@
+package json
+
+import (
+	"fmt"
+	"net"
+	"reflect"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	serializer "github.com/uol/serializer/json"
+)
+
+/**
+* Has unit tests for the custom type encoders.
+* @author rnojiri
+**/
+
+// Money - a type without json tags, like the ones from third party packages
+type Money struct {
+	Cents    int64
+	Currency string
+}
+
+// GeoPoint - another type without json tags
+type GeoPoint struct {
+	Lat float64
+	Lon float64
+}
+
+type AccountJSON struct {
+	Balance  Money            `json:"balance"`
+	Location GeoPoint         `json:"location"`
+	Address  net.IP           `json:"address"`
+	Limits   map[string]Money `json:"limits"`
+	History  []Money          `json:"history"`
+}
+
+// encodeMoney - encodes money as a decimal string
+func encodeMoney(dst []byte, v reflect.Value) ([]byte, error) {
+
+	m := v.Interface().(Money)
+	if m.Currency == "" {
+		return dst, fmt.Errorf("money without currency")
+	}
+
+	dst = append(dst, '"')
+	dst = strconv.AppendFloat(dst, float64(m.Cents)/100, 'f', 2, 64)
+	dst = append(dst, ' ')
+	dst = append(dst, m.Currency...)
+
+	return append(dst, '"'), nil
+}
+
+// encodeGeoPoint - encodes a geo point as a GeoJSON position
+func encodeGeoPoint(dst []byte, v reflect.Value) ([]byte, error) {
+
+	p := v.Interface().(GeoPoint)
+
+	dst = append(dst, '[')
+	dst = strconv.AppendFloat(dst, p.Lon, 'f', -1, 64)
+	dst = append(dst, ',')
+	dst = strconv.AppendFloat(dst, p.Lat, 'f', -1, 64)
+
+	return append(dst, ']'), nil
+}
+
+// encodeIP - encodes an IP address as a string
+func encodeIP(dst []byte, v reflect.Value) ([]byte, error) {
+
+	return strconv.AppendQuote(dst, v.Interface().(net.IP).String()), nil
+}
+
+// createEncoderSerializer - creates a serializer with all custom encoders registered
+func createEncoderSerializer(t *testing.T) *serializer.Serializer {
+
+	s := createSerializer()
+
+	encoders := map[reflect.Type]serializer.EncoderFunc{
+		reflect.TypeOf(Money{}):    encodeMoney,
+		reflect.TypeOf(GeoPoint{}): encodeGeoPoint,
+		reflect.TypeOf(net.IP{}):   encodeIP,
+	}
+
+	for encodedType, encoder := range encoders {
+		if !assert.NoError(t, s.RegisterEncoder(encodedType, encoder), "error registering the encoder") {
+			panic("register")
+		}
+	}
+
+	return s
+}
+
+// createAccount - creates a new account
+func createAccount() AccountJSON {
+
+	return AccountJSON{
+		Balance:  Money{Cents: 1050, Currency: "BRL"},
+		Location: GeoPoint{Lat: -23.5, Lon: -46.6},
+		Address:  net.ParseIP("10.0.0.1"),
+		Limits:   map[string]Money{"daily": {Cents: 100000, Currency: "BRL"}},
+		History:  []Money{{Cents: 1, Currency: "USD"}, {Cents: 250, Currency: "EUR"}},
+	}
+}
+
+// TestEncodersConstants - test if the constants are rendered by the custom encoders
+func TestEncodersConstants(t *testing.T) {
+
+	s := createEncoderSerializer(t)
+	addType(t, s, "account", createAccount())
+
+	expected := `{"balance":"10.50 BRL","location":[-46.6,-23.5],"address":"10.0.0.1","limits":{"daily":"1000.00 BRL"},"history":["0.01 USD","2.50 EUR"]}`
+	assert.Equal(t, expected, serialize(t, s, "account"), "expected the custom encoded constants")
+}
+
+// TestEncodersVariables - test if the variables are rendered by the custom encoders
+func TestEncodersVariables(t *testing.T) {
+
+	s := createEncoderSerializer(t)
+	addType(t, s, "account", createAccount(), "balance", "location", "address", "limits", "history[1]")
+
+	result := serialize(t, s, "account",
+		"balance", Money{Cents: 99, Currency: "USD"},
+		"location", GeoPoint{Lat: 1, Lon: 2},
+		"address", net.ParseIP("::1"),
+		"limits", map[string]Money{"monthly": {Cents: 5, Currency: "USD"}},
+		"history[1]", Money{Cents: 300, Currency: "BRL"},
+	)
+
+	expected := `{"balance":"0.99 USD","location":[2,1],"address":"::1","limits":{"monthly":"0.05 USD"},"history":["0.01 USD","3.00 BRL"]}`
+	assert.Equal(t, expected, result, "expected the custom encoded variables")
+
+	expected = `{"balance":"10.50 BRL","location":[-46.6,-23.5],"address":"10.0.0.1","limits":{"daily":"1000.00 BRL"},"history":["0.01 USD","2.50 EUR"]}`
+	assert.Equal(t, expected, serialize(t, s, "account"), "expected the custom encoded defaults")
+}
+
+// TestEncodersMarshal - test if the type mappings are compiled again after registering an encoder
+func TestEncodersMarshal(t *testing.T) {
+
+	s := createSerializer()
+
+	type Wallet struct {
+		Balance Money `json:"balance"`
+	}
+
+	wallet := Wallet{Balance: Money{Cents: 1, Currency: "BRL"}}
+
+	result, err := s.Marshal(wallet)
+	if assert.NoError(t, err, "error marshalling") {
+		assert.Equal(t, `{"balance":{}}`, result, "expected an untagged struct")
+	}
+
+	err = s.RegisterEncoder(reflect.TypeOf(Money{}), encodeMoney)
+	if !assert.NoError(t, err, "error registering the encoder") {
+		return
+	}
+
+	result, err = s.Marshal(wallet)
+	if assert.NoError(t, err, "error marshalling") {
+		assert.Equal(t, `{"balance":"0.01 BRL"}`, result, "expected the custom encoded value")
+	}
+}
+
+// TestEncodersErrors - test the encoder errors
+func TestEncodersErrors(t *testing.T) {
+
+	s := createEncoderSerializer(t)
+
+	account := createAccount()
+	addType(t, s, "account", account, "balance")
+
+	_, err := s.Serialize("account", "balance", Money{Cents: 1})
+	assert.Error(t, err, "expected the encoder error on a variable")
+
+	account.Balance.Currency = ""
+	err = s.Add("invalid", account)
+	assert.Error(t, err, "expected the encoder error on a constant")
+
+	addType(t, s, "noDefault", account, "balance")
+
+	_, err = s.Serialize("noDefault")
+	assert.Error(t, err, "expected no default value when the encoder fails")
+
+	assert.Error(t, s.RegisterEncoder(nil, encodeMoney), "expected an error with a null type")
+	assert.Error(t, s.RegisterEncoder(reflect.TypeOf(Money{}), nil), "expected an error with a null encoder")
+}
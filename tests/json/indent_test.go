@@ -0,0 +1,195 @@
+package json
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	serializer "github.com/uol/serializer/json"
+)
+
+/**
+* Has unit tests for the indented JSON serialization.
+* @author rnojiri
+**/
+
+// addIndentedType - add a new serialized type with the indented template
+func addIndentedType(t *testing.T, s *serializer.Serializer, name string, newType interface{}, vars ...string) {
+
+	err := s.AddWithOptions(name, newType, serializer.Variables(vars...), serializer.Indent(">", "\t"))
+	if !assert.NoError(t, err, "error adding a new serialization type") {
+		panic(err)
+	}
+}
+
+// serializeIndent - try to serialize the named type using the indented template
+func serializeIndent(t *testing.T, s *serializer.Serializer, name string, params ...interface{}) string {
+
+	result, err := s.SerializeIndent(name, params...)
+	if !assert.NoError(t, err, "error serializing the type: %s", name) {
+		panic(err)
+	}
+
+	return result
+}
+
+// marshalIndent - returns the native indented output
+func marshalIndent(t *testing.T, item interface{}) string {
+
+	result, err := json.MarshalIndent(item, ">", "\t")
+	if !assert.NoError(t, err, "error marshalling the item") {
+		panic(err)
+	}
+
+	return string(result)
+}
+
+// TestIndentNoVariables - test the indented output without variables
+func TestIndentNoVariables(t *testing.T) {
+
+	newType := SimpleJSON{
+		Text:    `"indented" \ text`,
+		Integer: 10,
+		Float:   1.5,
+		Boolean: true,
+	}
+
+	s := createSerializer()
+	addIndentedType(t, s, "s", newType)
+
+	assert.Equal(t, marshalIndent(t, newType), serializeIndent(t, s, "s"), "expected same output")
+}
+
+// TestIndentVariables - test the indented output with sub objects and scalar variables
+func TestIndentVariables(t *testing.T) {
+
+	newType := ComplexTypeJSON{
+		Simple: SimpleJSON{
+			Text:    "indented",
+			Integer: 1,
+		},
+		CollectionJSON: CollectionJSON{
+			Mapping: map[string]int{"1": 1},
+			Array:   []float64{1.5, 2.5},
+		},
+	}
+
+	s := createSerializer()
+	addIndentedType(t, s, "s", newType, "simple.text", "simple.boolean", "mapping.1")
+
+	result := serializeIndent(t, s, "s",
+		"simple.text", "changed",
+		"simple.boolean", true,
+		"mapping.1", 7,
+	)
+
+	expected := newType
+	expected.Simple.Text = "changed"
+	expected.Simple.Boolean = true
+	expected.Mapping = map[string]int{"1": 7}
+
+	assert.Equal(t, marshalIndent(t, expected), result, "expected same output")
+}
+
+// TestIndentCollectionVariables - test the indented output of map and array variables
+func TestIndentCollectionVariables(t *testing.T) {
+
+	newType := ComplexTypeJSON{
+		CollectionJSON: CollectionJSON{
+			Mapping: map[string]int{"1": 1},
+			Array:   []float64{1.5},
+		},
+	}
+
+	s := createSerializer()
+	addIndentedType(t, s, "s", newType, "mapping", "array")
+
+	assert.Equal(t, marshalIndent(t, newType), serializeIndent(t, s, "s"), "expected same output with the default values")
+
+	result := serializeIndent(t, s, "s",
+		"mapping", map[string]int{},
+		"array", []float64{1.5, 2.5, 3.5},
+	)
+
+	expected := newType
+	expected.Mapping = map[string]int{}
+	expected.Array = []float64{1.5, 2.5, 3.5}
+
+	assert.Equal(t, marshalIndent(t, expected), result, "expected same output")
+
+	compact := serialize(t, s, "s")
+	assert.NotContains(t, compact, "\n", "expected the compact template to be kept")
+}
+
+// TestIndentCompatCollectionVariables - test the indented output of map and array variables in compat mode, written
+// as whole values (null when nil)
+func TestIndentCompatCollectionVariables(t *testing.T) {
+
+	newType := ComplexTypeJSON{
+		CollectionJSON: CollectionJSON{
+			Mapping: map[string]int{"b": 2, "a": 1},
+			Array:   []float64{1.5},
+		},
+	}
+
+	s := createCompatSerializer()
+
+	err := s.AddWithOptions("s", newType, serializer.Variables("mapping", "array"), serializer.Indent(">", "\t"))
+	if !assert.NoError(t, err, "error adding the mapping") {
+		return
+	}
+
+	assert.Equal(t, marshalIndent(t, newType), serializeIndent(t, s, "s"), "expected same output with the default values")
+
+	expected := newType
+	expected.Mapping = map[string]int{"c": 3, "d": 4}
+	expected.Array = nil
+
+	result := serializeIndent(t, s, "s", "mapping", expected.Mapping, "array", expected.Array)
+	assert.Equal(t, marshalIndent(t, expected), result, "expected same output")
+
+	compact := serialize(t, s, "s")
+	assert.Equal(t, stdMarshal(t, newType), compact, "expected the compact template to be kept")
+}
+
+// TestIndentInterfaceVariables - test the indented output of the objects and arrays of interface variables
+func TestIndentInterfaceVariables(t *testing.T) {
+
+	type AnyJSON struct {
+		Name  string      `json:"name"`
+		Value interface{} `json:"value"`
+		Other interface{} `json:"other"`
+	}
+
+	newType := AnyJSON{
+		Name:  "any",
+		Value: map[string]interface{}{"list": []interface{}{1, "two", map[string]bool{"three": true}}},
+		Other: []int{},
+	}
+
+	s := createSerializer()
+	addIndentedType(t, s, "s", newType, "value", "other")
+
+	assert.Equal(t, marshalIndent(t, newType), serializeIndent(t, s, "s"), "expected same output with the default values")
+
+	for _, value := range []interface{}{[]interface{}{[]int{1}, []int{}}, "text", 1.5} {
+
+		expected := newType
+		expected.Value = value
+		expected.Other = map[string]int{"a": 1}
+
+		result := serializeIndent(t, s, "s", "value", value, "other", expected.Other)
+		assert.Equal(t, marshalIndent(t, expected), result, "expected same output with the value: %v", value)
+	}
+}
+
+// TestIndentNotConfigured - test the indented serialization of a mapping without the indent option
+func TestIndentNotConfigured(t *testing.T) {
+
+	s := createSerializer()
+	addType(t, s, "s", SimpleJSON{})
+
+	_, err := s.SerializeIndent("s")
+	assert.Error(t, err, "expected an error without the indented template")
+}
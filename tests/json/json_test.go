@@ -599,3 +599,19 @@ func TestNullValue(t *testing.T) {
 	_, err = s.SerializeArray(items...)
 	tests.CheckNullErrorValidation(t, err)
 }
+
+// TestPoolStats - test if the buffer pool is used on serialization
+func TestPoolStats(t *testing.T) {
+
+	s := createSerializer()
+	addType(t, s, "s", SimpleJSON{Text: "pool"}, "integer")
+
+	const numSerializations = 10
+
+	for i := 0; i < numSerializations; i++ {
+		serialize(t, s, "s", "integer", i)
+	}
+
+	stats := s.PoolStats()
+	assert.Equal(t, uint64(numSerializations), stats.Hits+stats.Misses, "expected one buffer per serialization")
+}
@@ -0,0 +1,199 @@
+package json
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	serializer "github.com/uol/serializer/json"
+)
+
+/**
+* Has unit tests for the non string map keys and the key variables.
+* @author rnojiri
+**/
+
+// Region - an enum like named string type
+type Region string
+
+// Level - an integer type with a text form
+type Level int
+
+// MarshalText - returns the level name
+func (l Level) MarshalText() ([]byte, error) {
+
+	switch l {
+	case 0:
+		return []byte("debug"), nil
+	case 1:
+		return []byte("info"), nil
+	default:
+		return nil, fmt.Errorf("unknown level: %d", int(l))
+	}
+}
+
+// UnmarshalText - parses the level name
+func (l *Level) UnmarshalText(text []byte) error {
+
+	switch string(text) {
+	case "debug":
+		*l = 0
+	case "info":
+		*l = 1
+	default:
+		return fmt.Errorf("unknown level: %s", string(text))
+	}
+
+	return nil
+}
+
+type HostsJSON struct {
+	Metric string                 `json:"metric"`
+	Hosts  map[string]interface{} `json:"hosts"`
+	Levels map[Level]int          `json:"levels"`
+}
+
+type KeysJSON struct {
+	ByID     map[int]string    `json:"byID"`
+	BySize   map[uint8]bool    `json:"bySize"`
+	ByRegion map[Region]int    `json:"byRegion"`
+	ByLevel  map[Level]float64 `json:"byLevel"`
+}
+
+// TestIntegerKeys - test the integer and named string keys as constants and variables
+func TestIntegerKeys(t *testing.T) {
+
+	s := serializer.NewWithOptions(serializer.WithSortedKeys())
+
+	item := KeysJSON{
+		ByID:     map[int]string{-1: "a", 10: "b", 2: "c"},
+		BySize:   map[uint8]bool{255: true},
+		ByRegion: map[Region]int{"sa-east": 1},
+		ByLevel:  map[Level]float64{0: 0.5, 1: 1.5},
+	}
+
+	addType(t, s, "constant", item)
+	addType(t, s, "variable", item, "byID", "bySize", "byRegion", "byLevel")
+	addType(t, s, "value", item, "byID.10", "byLevel.info")
+
+	expected := `{"byID":{"-1":"a","10":"b","2":"c"},"bySize":{"255":true},"byRegion":{"sa-east":1},"byLevel":{"debug":0.5,"info":1.5}}`
+
+	assert.Equal(t, expected, serialize(t, s, "constant"), "expected the keys as strings")
+	assert.Equal(t, expected, serialize(t, s, "variable"), "expected the keys as strings")
+
+	result := serialize(t, s, "variable", "byID", map[int]string{7: "x"}, "byRegion", map[Region]int{"us": 2})
+	assert.Equal(t, `{"byID":{"7":"x"},"bySize":{"255":true},"byRegion":{"us":2},"byLevel":{"debug":0.5,"info":1.5}}`, result, "expected the variable keys as strings")
+
+	result = serialize(t, s, "value", "byID.10", "z", "byLevel.info", 9.5)
+	assert.True(t, strings.Contains(result, `"10":"z"`), "expected the integer key variable: %s", result)
+	assert.True(t, strings.Contains(result, `"info":9.5`), "expected the text key variable: %s", result)
+}
+
+// TestKeysParseInto - test parsing back the non string keys
+func TestKeysParseInto(t *testing.T) {
+
+	s := createSerializer()
+
+	item := KeysJSON{
+		ByID:    map[int]string{10: "b"},
+		ByLevel: map[Level]float64{1: 1.5},
+	}
+
+	addType(t, s, "value", item, "byID.10", "byLevel.info")
+
+	result := serialize(t, s, "value", "byID.10", "z", "byLevel.info", 2.5)
+
+	actual := KeysJSON{}
+	err := s.ParseInto("value", []byte(result), &actual)
+	if !assert.NoError(t, err, "error parsing into the struct") {
+		return
+	}
+
+	assert.Equal(t, map[int]string{10: "z"}, actual.ByID, "expected the integer key")
+	assert.Equal(t, map[Level]float64{1: 2.5}, actual.ByLevel, "expected the text key")
+}
+
+// TestUnsupportedKeys - test the keys without a JSON form
+func TestUnsupportedKeys(t *testing.T) {
+
+	s := createSerializer()
+
+	type FloatKeysJSON struct {
+		Values map[float64]int `json:"values"`
+	}
+
+	err := s.Add("float", FloatKeysJSON{Values: map[float64]int{1.5: 1}})
+	assert.Error(t, err, "expected an error with float keys")
+
+	err = s.Add("level", KeysJSON{ByLevel: map[Level]float64{5: 1}})
+	assert.Error(t, err, "expected the text marshaller error")
+
+	addType(t, s, "variable", KeysJSON{}, "byLevel")
+
+	_, err = s.Serialize("variable", "byLevel", map[Level]float64{5: 1})
+	assert.Error(t, err, "expected the text marshaller error on a variable")
+}
+
+// TestKeyVariables - test the map keys bound as variables, independently of their values
+func TestKeyVariables(t *testing.T) {
+
+	s := serializer.NewWithOptions(serializer.WithSortedKeys())
+
+	item := HostsJSON{
+		Metric: "cpu",
+		Hosts:  map[string]interface{}{"host": map[string]int{"user": 1}, "static": true},
+		Levels: map[Level]int{1: 10},
+	}
+
+	addType(t, s, "key", item, "hosts.@host")
+	addType(t, s, "both", item, "hosts.@host", "hosts.host", "levels.@info")
+
+	expected := `{"metric":"cpu","hosts":{"host":{"user":1},"static":true},"levels":{"info":10}}`
+	assert.Equal(t, expected, serialize(t, s, "key"), "expected the mapped key as default")
+	assert.Equal(t, expected, serialize(t, s, "both"), "expected the mapped key and value as default")
+
+	result := serialize(t, s, "key", "hosts.@host", `web-01 "%s"`)
+	assert.Equal(t, `{"metric":"cpu","hosts":{"web-01 \"%s\"":{"user":1},"static":true},"levels":{"info":10}}`, result, "expected the escaped key")
+
+	result = serialize(t, s, "both", "hosts.host", map[string]int{"idle": 2}, "hosts.@host", "db", "levels.@info", Level(0))
+	assert.Equal(t, `{"metric":"cpu","hosts":{"db":{"idle":2},"static":true},"levels":{"debug":10}}`, result, "expected the key and value variables")
+
+	result = serialize(t, s, "both", "hosts.host", 5)
+	assert.Equal(t, `{"metric":"cpu","hosts":{"host":5,"static":true},"levels":{"info":10}}`, result, "expected only the value variable")
+
+	values, err := s.Parse("both", []byte(`{"metric":"cpu","hosts":{"db":{"idle":2},"static":true},"levels":{"debug":10}}`))
+	if assert.NoError(t, err, "error parsing the key variables") {
+		assert.Equal(t, "db", values["hosts.@host"], "expected the parsed key")
+		assert.Equal(t, "debug", values["levels.@info"], "expected the parsed key")
+	}
+
+	hosts := schema(t, s, "key")["properties"].(map[string]interface{})["hosts"].(map[string]interface{})
+	assert.Equal(t, []interface{}{"static"}, hosts["required"], "expected only the constant key as required")
+	assert.Equal(t, map[string]interface{}{"const": map[string]interface{}{"user": 1.0}}, hosts["additionalProperties"], "expected the key variable value as additional property")
+	assert.Equal(t, 2.0, hosts["minProperties"], "expected the number of keys as minimum")
+
+	_, err = s.Serialize("both", "hosts.@host", nil)
+	assert.Error(t, err, "expected an error with a null key")
+
+	_, err = s.Serialize("both", "levels.@info", Level(7))
+	assert.Error(t, err, "expected the text marshaller error")
+
+	_, err = s.Serialize("both", "hosts.@host", 1.5)
+	assert.Error(t, err, "expected an error with a float key")
+
+	err = s.ParseInto("both", []byte(expected), &HostsJSON{})
+	assert.Error(t, err, "expected an error parsing a key variable into a struct")
+}
+
+// TestKeyVariablesSortedModes - test if the key variables are rejected by the modes which sort the keys on output
+func TestKeyVariablesSortedModes(t *testing.T) {
+
+	item := HostsJSON{Hosts: map[string]interface{}{"host": 1}}
+
+	for _, s := range []*serializer.Serializer{createCompatSerializer(), createCanonicalSerializer()} {
+		err := s.Add("key", item, "hosts.@host")
+		assert.Error(t, err, "expected an error with a key variable")
+	}
+}
@@ -0,0 +1,96 @@
+package json
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	serializer "github.com/uol/serializer/json"
+	common "github.com/uol/serializer/serializer"
+	"github.com/uol/serializer/tests"
+)
+
+/**
+* Has unit tests for the lenient JSON array serialization.
+* @author rnojiri
+**/
+
+// TestSerializeArrayLenient - test if the invalid items are skipped and reported
+func TestSerializeArrayLenient(t *testing.T) {
+
+	s, items := createEncoderItems(t, 5)
+
+	items[1] = &serializer.ArrayItem{Name: "unknown"}
+	items[3] = &serializer.ArrayItem{Name: "s", Parameters: []interface{}{"integer", nil}}
+
+	result, err := s.SerializeArrayLenient(items...)
+	if !assert.Error(t, err, "expected an error") {
+		return
+	}
+
+	batchErr, ok := err.(*common.BatchError)
+	if !assert.True(t, ok, "expected a batch error") {
+		return
+	}
+
+	if assert.Len(t, batchErr.Errors, 2, "expected two failed items") {
+		assert.Equal(t, 1, batchErr.Errors[0].Index, "expected the index of the first invalid item")
+		assert.Equal(t, 3, batchErr.Errors[1].Index, "expected the index of the second invalid item")
+		tests.CheckNullErrorValidation(t, batchErr.Errors[1].Err)
+	}
+
+	expected, err := s.SerializeArray(items[0], items[2], items[4])
+	if !assert.NoError(t, err, "error serializing the valid items") {
+		return
+	}
+
+	assert.Equal(t, expected, result, "expected only the valid items")
+
+	result, err = s.SerializeArrayLenient(items[0], items[2])
+	assert.NoError(t, err, "expected no error with valid items")
+
+	expected, _ = s.SerializeArray(items[0], items[2])
+	assert.Equal(t, expected, result, "expected same output of the strict version")
+}
+
+// TestSerializeArrayLenientNothingSerialized - test if an empty array is returned without items or when all items fail
+func TestSerializeArrayLenientNothingSerialized(t *testing.T) {
+
+	s, _ := createEncoderItems(t, 0)
+
+	result, err := s.SerializeArrayLenient()
+	assert.NoError(t, err, "expected no error without items")
+	assert.Equal(t, "[]", result, "expected an empty array without items")
+
+	result, err = s.SerializeArrayLenient(&serializer.ArrayItem{Name: "unknown"}, &serializer.ArrayItem{Name: "s", Parameters: []interface{}{"integer", nil}})
+	if assert.Error(t, err, "expected an error") {
+		if batchErr, ok := err.(*common.BatchError); assert.True(t, ok, "expected a batch error") {
+			assert.Len(t, batchErr.Errors, 2, "expected all items failed")
+		}
+	}
+
+	assert.Equal(t, "[]", result, "expected an empty array when all items fail")
+}
+
+// TestSerializeArrayLenientPanic - test if an unexpected panic is reported as the error of its item
+func TestSerializeArrayLenientPanic(t *testing.T) {
+
+	s, items := createEncoderItems(t, 2)
+	fuse := addPanicItem(t, s)
+
+	result, err := s.SerializeArrayLenient(items[0], fuse, items[1])
+	if !assert.Error(t, err, "expected an error") {
+		return
+	}
+
+	if batchErr, ok := err.(*common.BatchError); assert.True(t, ok, "expected a batch error") {
+		if assert.Len(t, batchErr.Errors, 1, "expected one failed item") {
+			assert.Equal(t, 1, batchErr.Errors[0].Index, "expected the index of the panicking item")
+		}
+	}
+
+	expected, err := s.SerializeArray(items...)
+	if assert.NoError(t, err, "error serializing the valid items") {
+		assert.Equal(t, expected, result, "expected only the valid items")
+	}
+}
@@ -0,0 +1,174 @@
+package json
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	serializer "github.com/uol/serializer/json"
+)
+
+/**
+* Has unit tests for the JSON Lines serialization.
+* @author rnojiri
+**/
+
+// createLineItems - creates the serializer and the items used by the JSON Lines tests
+func createLineItems(t *testing.T) (*serializer.Serializer, []*serializer.ArrayItem, string) {
+
+	s := createSerializer()
+	addType(t, s, "s", SimpleJSON{Text: "line"}, "integer")
+
+	items := []*serializer.ArrayItem{
+		{Name: "s", Parameters: []interface{}{"integer", 1}},
+		{Name: "s", Parameters: []interface{}{"integer", 2}},
+		{Name: "s", Parameters: []interface{}{"integer", 3}},
+	}
+
+	var expected strings.Builder
+	for _, item := range items {
+		expected.WriteString(serialize(t, s, item.Name, item.Parameters...))
+		expected.WriteString("\n")
+	}
+
+	return s, items, expected.String()
+}
+
+// TestSerializeLines - test serializing items as JSON Lines
+func TestSerializeLines(t *testing.T) {
+
+	s, items, expected := createLineItems(t)
+
+	result, err := s.SerializeLines(items...)
+	if !assert.NoError(t, err, "error serializing lines") {
+		return
+	}
+
+	assert.Equal(t, expected, result, "expected one json per line")
+
+	for _, line := range strings.Split(strings.TrimSuffix(result, "\n"), "\n") {
+		actual := SimpleJSON{}
+		if !assert.NoError(t, json.Unmarshal([]byte(line), &actual), "expected a valid json line") {
+			return
+		}
+	}
+
+	result, err = s.SerializeLines()
+	assert.NoError(t, err, "expected no error without items")
+	assert.Empty(t, result, "expected no output without items")
+}
+
+// TestAppendAndWriteLines - test the append and writer variants
+func TestAppendAndWriteLines(t *testing.T) {
+
+	s, items, expected := createLineItems(t)
+
+	result, err := s.AppendLines([]byte("prefix\n"), items...)
+	if !assert.NoError(t, err, "error appending lines") {
+		return
+	}
+
+	assert.Equal(t, "prefix\n"+expected, string(result), "expected the lines appended")
+
+	var buffer bytes.Buffer
+	err = s.WriteLines(&buffer, items...)
+	if !assert.NoError(t, err, "error writing lines") {
+		return
+	}
+
+	assert.Equal(t, expected, buffer.String(), "expected the lines written")
+
+	_, err = s.AppendLines(nil, &serializer.ArrayItem{Name: "unknown"})
+	assert.Error(t, err, "expected an error with an unknown mapping")
+}
+
+// TestStreamLines - test writing the lines received from a channel
+func TestStreamLines(t *testing.T) {
+
+	s, items, expected := createLineItems(t)
+
+	channel := make(chan *serializer.ArrayItem)
+
+	go func() {
+		for _, item := range items {
+			channel <- item
+		}
+		close(channel)
+	}()
+
+	var buffer bytes.Buffer
+	err := s.StreamLines(&buffer, channel)
+	if !assert.NoError(t, err, "error streaming lines") {
+		return
+	}
+
+	assert.Equal(t, expected, buffer.String(), "expected the lines streamed")
+
+	channel = make(chan *serializer.ArrayItem, 3)
+	channel <- items[0]
+	channel <- &serializer.ArrayItem{Name: "unknown"}
+	channel <- items[1]
+	close(channel)
+
+	buffer.Reset()
+	err = s.StreamLines(&buffer, channel)
+	assert.Error(t, err, "expected an error with an unknown mapping")
+	assert.Equal(t, strings.SplitAfter(expected, "\n")[0], buffer.String(), "expected only the lines before the error")
+	assert.Len(t, channel, 0, "expected the channel to be consumed")
+}
+
+// TestLinesEscapedNewLine - test if the new lines inside the strings are escaped, keeping one json per line
+func TestLinesEscapedNewLine(t *testing.T) {
+
+	s := createSerializer()
+	addType(t, s, "constant", SimpleJSON{Text: "first\nsecond\r\n"})
+	addType(t, s, "variable", SimpleJSON{}, "text")
+
+	result, err := s.SerializeLines(
+		&serializer.ArrayItem{Name: "constant"},
+		&serializer.ArrayItem{Name: "variable", Parameters: []interface{}{"text", "third\nfourth"}},
+	)
+
+	if !assert.NoError(t, err, "error serializing lines") {
+		return
+	}
+
+	lines := strings.Split(strings.TrimSuffix(result, "\n"), "\n")
+	if !assert.Len(t, lines, 2, "expected one line per item") {
+		return
+	}
+
+	for i, text := range []string{"first\nsecond\r\n", "third\nfourth"} {
+		actual := SimpleJSON{}
+		if assert.NoError(t, json.Unmarshal([]byte(lines[i]), &actual), "expected a valid json line") {
+			assert.Equal(t, text, actual.Text, "expected the original text")
+		}
+	}
+}
+
+// TestLinesPanic - test if an unexpected panic is returned as an error instead of writing an empty line
+func TestLinesPanic(t *testing.T) {
+
+	s, items, expected := createLineItems(t)
+	fuse := addPanicItem(t, s)
+
+	_, err := s.SerializeLines(items[0], fuse, items[1])
+	assert.Error(t, err, "expected the panic as an error")
+
+	var buffer bytes.Buffer
+	err = s.WriteLines(&buffer, fuse)
+	assert.Error(t, err, "expected the panic as an error")
+	assert.Empty(t, buffer.String(), "expected nothing written")
+
+	channel := make(chan *serializer.ArrayItem, 2)
+	channel <- items[0]
+	channel <- fuse
+	close(channel)
+
+	err = s.StreamLines(&buffer, channel)
+	assert.Error(t, err, "expected the panic as an error")
+	assert.Equal(t, strings.SplitAfter(expected, "\n")[0], buffer.String(), "expected only the lines before the panic")
+}
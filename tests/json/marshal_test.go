@@ -0,0 +1,212 @@
+package json
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	gotest "github.com/uol/gotest/utils"
+)
+
+/**
+* Has unit tests for the type keyed JSON serialization.
+* @author rnojiri
+**/
+
+type StdMarshalJSON struct {
+	Small   float64          `json:"small"`
+	Half    float64          `json:"half"`
+	Single  float32          `json:"single"`
+	Big     float64          `json:"big"`
+	Text    string           `json:"text"`
+	Mapping map[string]int   `json:"mapping"`
+	Array   []string         `json:"array"`
+	Nested  map[string][]int `json:"nested"`
+}
+
+type PartiallyTaggedJSON struct {
+	Text     string `json:"text"`
+	Unsigned uint32 `json:"unsigned"`
+	internal int
+	Ignored  string
+}
+
+// marshal - try to marshal the item
+func marshal(t *testing.T, item interface{}) string {
+
+	s := createSerializer()
+
+	result, err := s.Marshal(item)
+	if !assert.NoError(t, err, "error marshalling the item: %+v", item) {
+		panic(err)
+	}
+
+	return result
+}
+
+// TestMarshalSimple - test marshalling a simple struct without a previous mapping
+func TestMarshalSimple(t *testing.T) {
+
+	newType := SimpleJSON{
+		Boolean: true,
+		Float:   float64(gotest.RandomInt(0, 100)) + 0.5,
+		Integer: gotest.RandomInt(0, 1000),
+		Text:    `"marshal"\`,
+	}
+
+	result := marshal(t, newType)
+
+	actual := SimpleJSON{}
+	validateJSON(t, result, &newType, &actual)
+}
+
+// TestMarshalComplexType - test marshalling a struct with sub objects, maps and arrays
+func TestMarshalComplexType(t *testing.T) {
+
+	newType := ComplexTypeJSON{
+		Simple: SimpleJSON{
+			Boolean: false,
+			Float:   -1.5,
+			Integer: 7,
+			Text:    "complex",
+		},
+		CollectionJSON: CollectionJSON{
+			Mapping: map[string]int{
+				"1": 1,
+				"2": 2,
+			},
+			Array: []float64{1.5, 2.5},
+		},
+	}
+
+	result := marshal(t, newType)
+
+	actual := ComplexTypeJSON{}
+	validateJSON(t, result, &newType, &actual)
+}
+
+// TestMarshalReusesTemplate - test marshalling different values of the same type
+func TestMarshalReusesTemplate(t *testing.T) {
+
+	s := createSerializer()
+
+	for i := 0; i < 10; i++ {
+
+		newType := SimpleJSON{
+			Boolean: i%2 == 0,
+			Float:   float64(i),
+			Integer: i,
+			Text:    "reused",
+		}
+
+		result, err := s.Marshal(newType)
+		if !assert.NoError(t, err, "error marshalling the item") {
+			return
+		}
+
+		actual := SimpleJSON{}
+		if !validateJSON(t, result, &newType, &actual) {
+			return
+		}
+	}
+}
+
+// TestMarshalUntaggedFields - test marshalling a struct with untagged fields in the end
+func TestMarshalUntaggedFields(t *testing.T) {
+
+	newType := PartiallyTaggedJSON{
+		Text:     "partial",
+		Unsigned: 10,
+		internal: 1,
+		Ignored:  "ignored",
+	}
+
+	result := marshal(t, newType)
+
+	expected := PartiallyTaggedJSON{
+		Text:     newType.Text,
+		Unsigned: newType.Unsigned,
+	}
+
+	actual := PartiallyTaggedJSON{}
+	validateJSON(t, result, &expected, &actual)
+}
+
+// TestMarshalInvalidKind - test marshalling a non struct value
+func TestMarshalInvalidKind(t *testing.T) {
+
+	s := createSerializer()
+
+	_, err := s.Marshal(map[string]int{"1": 1})
+	assert.Error(t, err, "expected an error marshalling a map")
+}
+
+// TestMarshalPointer - test marshalling pointers to structs and nil pointers like encoding/json
+func TestMarshalPointer(t *testing.T) {
+
+	newType := SimpleJSON{
+		Boolean: true,
+		Float:   2.5,
+		Integer: 3,
+		Text:    "pointer",
+	}
+
+	result := marshal(t, &newType)
+	assert.Equal(t, marshal(t, newType), result, "expected the same output of the struct")
+
+	pointer := &newType
+	assert.Equal(t, result, marshal(t, &pointer), "expected the pointers followed to the struct")
+
+	var nilPointer *SimpleJSON
+	assert.Equal(t, "null", marshal(t, nilPointer), "expected null with a nil pointer")
+	assert.Equal(t, "null", marshal(t, &nilPointer), "expected null with a pointer to a nil pointer")
+}
+
+// TestMarshalLikeStandardLibrary - test if the floats, the control characters and the nil collections are written
+// byte for byte like encoding/json does
+func TestMarshalLikeStandardLibrary(t *testing.T) {
+
+	items := []StdMarshalJSON{
+		{},
+		{
+			Small:  1e-9,
+			Half:   2.5,
+			Single: 0.1,
+			Big:    1e21,
+			Text:   "a\nb\r\tc\x01",
+		},
+		{
+			Small:   -0.000001,
+			Half:    100,
+			Single:  3.4e38,
+			Big:     123456789.125,
+			Text:    "line\n",
+			Mapping: map[string]int{"a": 1},
+			Array:   []string{"x\ny"},
+			Nested:  map[string][]int{"n": nil},
+		},
+	}
+
+	s := createSerializer()
+
+	for _, item := range items {
+
+		result, err := s.Marshal(item)
+		if assert.NoError(t, err, "error marshalling the item: %+v", item) {
+			assert.Equal(t, stdMarshal(t, item), result, "expected the same output of encoding/json")
+		}
+	}
+
+	item := items[1]
+	item.Mapping = map[string]int{}
+	item.Array = []string{}
+	item.Nested = map[string][]int{}
+
+	addType(t, s, "constants", item)
+	addType(t, s, "variables", item, "small", "half", "single", "big", "text")
+
+	expected := stdMarshal(t, item)
+	assert.Equal(t, expected, serialize(t, s, "constants"), "expected the constants like encoding/json")
+	assert.Equal(t, expected, serialize(t, s, "variables"), "expected the default variables like encoding/json")
+	assert.Equal(t, expected, serialize(t, s, "variables", "small", 1e-9, "half", 2.5, "single", float32(0.1), "big", 1e21, "text", "a\nb\r\tc\x01"), "expected the variables like encoding/json")
+}
@@ -0,0 +1,135 @@
+package json
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	serializer "github.com/uol/serializer/json"
+)
+
+/**
+* Has unit tests for the naming strategies and the tag key.
+* @author rnojiri
+**/
+
+type UntaggedAddress struct {
+	StreetName string
+	ZipCode    int
+}
+
+type UntaggedBase struct {
+	CreatedAt int64
+}
+
+type UntaggedUser struct {
+	UntaggedBase
+	UserID      int
+	HTTPServer  string
+	Address     UntaggedAddress
+	Tagged      bool `json:"is_tagged"`
+	privateData string
+}
+
+type MsgpackJSON struct {
+	Name  string `msgpack:"n" json:"name"`
+	Value int    `msgpack:"v"`
+	Other bool   `json:"other"`
+}
+
+// TestNamingStrategyFunctions - test the predefined naming strategies
+func TestNamingStrategyFunctions(t *testing.T) {
+
+	cases := map[string][3]string{
+		"UserID":        {"userID", "user_id", "user-id"},
+		"HTTPServer":    {"httpServer", "http_server", "http-server"},
+		"ID":            {"id", "id", "id"},
+		"Value2":        {"value2", "value2", "value2"},
+		"Name":          {"name", "name", "name"},
+		"XMLHttpReq":    {"xmlHttpReq", "xml_http_req", "xml-http-req"},
+		"Already_Snake": {"alreadySnake", "already_snake", "already-snake"},
+	}
+
+	for name, expected := range cases {
+		assert.Equal(t, expected[0], serializer.CamelCase(name), "unexpected camel case of %s", name)
+		assert.Equal(t, expected[1], serializer.SnakeCase(name), "unexpected snake case of %s", name)
+		assert.Equal(t, expected[2], serializer.KebabCase(name), "unexpected kebab case of %s", name)
+	}
+
+	assert.Equal(t, "UserID", serializer.FieldName("UserID"), "expected the field name")
+}
+
+// createUntaggedUser - creates an untagged user
+func createUntaggedUser() UntaggedUser {
+
+	return UntaggedUser{
+		UntaggedBase: UntaggedBase{CreatedAt: 10},
+		UserID:       1,
+		HTTPServer:   "localhost",
+		Address:      UntaggedAddress{StreetName: "main", ZipCode: 123},
+		Tagged:       true,
+		privateData:  "secret",
+	}
+}
+
+// TestNamingStrategy - test the untagged fields named by each strategy
+func TestNamingStrategy(t *testing.T) {
+
+	cases := map[string]serializer.NamingStrategy{
+		`{"CreatedAt":10,"UserID":1,"HTTPServer":"localhost","Address":{"StreetName":"main","ZipCode":123},"is_tagged":true}`:      serializer.FieldName,
+		`{"createdAt":10,"userID":1,"httpServer":"localhost","address":{"streetName":"main","zipCode":123},"is_tagged":true}`:      serializer.CamelCase,
+		`{"created_at":10,"user_id":1,"http_server":"localhost","address":{"street_name":"main","zip_code":123},"is_tagged":true}`: serializer.SnakeCase,
+		`{"created-at":10,"user-id":1,"http-server":"localhost","address":{"street-name":"main","zip-code":123},"is_tagged":true}`: serializer.KebabCase,
+		`{"CREATEDAT":10,"USERID":1,"HTTPSERVER":"localhost","ADDRESS":{"STREETNAME":"main","ZIPCODE":123},"is_tagged":true}`:      strings.ToUpper,
+	}
+
+	for expected, strategy := range cases {
+
+		s := serializer.NewWithOptions(serializer.WithNamingStrategy(strategy))
+		addType(t, s, "user", createUntaggedUser())
+
+		assert.Equal(t, expected, serialize(t, s, "user"), "expected the named properties")
+	}
+
+	s := createSerializer()
+	addType(t, s, "user", createUntaggedUser())
+
+	assert.Equal(t, `{"is_tagged":true}`, serialize(t, s, "user"), "expected only the tagged fields by default")
+}
+
+// TestNamingStrategyVariables - test the variables and parsing with the named properties
+func TestNamingStrategyVariables(t *testing.T) {
+
+	s := serializer.NewWithOptions(serializer.WithNamingStrategy(serializer.SnakeCase))
+	addType(t, s, "user", createUntaggedUser(), "user_id", "address.zip_code")
+
+	result := serialize(t, s, "user", "user_id", 2, "address.zip_code", 456)
+	assert.Equal(t, `{"created_at":10,"user_id":2,"http_server":"localhost","address":{"street_name":"main","zip_code":456},"is_tagged":true}`, result, "expected the variables")
+
+	actual := UntaggedUser{}
+	err := s.ParseInto("user", []byte(result), &actual)
+	if assert.NoError(t, err, "error parsing into the struct") {
+		assert.Equal(t, 2, actual.UserID, "expected the parsed user id")
+		assert.Equal(t, 456, actual.Address.ZipCode, "expected the parsed zip code")
+	}
+
+	result, err = s.Marshal(createUntaggedUser())
+	if assert.NoError(t, err, "error marshalling") {
+		assert.Equal(t, `{"created_at":10,"user_id":1,"http_server":"localhost","address":{"street_name":"main","zip_code":123},"is_tagged":true}`, result, "expected the named properties")
+	}
+}
+
+// TestTagKey - test if other tag keys drive the mapping
+func TestTagKey(t *testing.T) {
+
+	s := serializer.NewWithOptions(serializer.WithTagKey("msgpack"))
+	addType(t, s, "s", MsgpackJSON{Name: "a", Value: 1, Other: true}, "v")
+
+	assert.Equal(t, `{"n":"a","v":2}`, serialize(t, s, "s", "v", 2), "expected the msgpack tags")
+
+	s = serializer.NewWithOptions(serializer.WithTagKey("msgpack"), serializer.WithNamingStrategy(serializer.CamelCase))
+	addType(t, s, "s", MsgpackJSON{Name: "a", Value: 1, Other: true})
+
+	assert.Equal(t, `{"n":"a","v":1,"other":true}`, serialize(t, s, "s"), "expected the msgpack tags and the named field")
+}
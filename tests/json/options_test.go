@@ -0,0 +1,167 @@
+package json
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	serializer "github.com/uol/serializer/json"
+	common "github.com/uol/serializer/serializer"
+)
+
+/**
+* Has unit tests for the JSON serializer options.
+* @author rnojiri
+**/
+
+// recordingLogger - records the logged messages
+type recordingLogger struct {
+	messages []string
+}
+
+// Printf - records the message
+func (l *recordingLogger) Printf(format string, v ...interface{}) {
+
+	l.messages = append(l.messages, fmt.Sprintf(format, v...))
+}
+
+// TestNewShortcut - test if the default constructor keeps the default behaviour
+func TestNewShortcut(t *testing.T) {
+
+	s := serializer.NewWithOptions(serializer.WithBufferSize(100))
+	addType(t, s, "s", SimpleJSON{Text: "a", Integer: 1, Float: 1.5, Boolean: true}, "float")
+
+	assert.Equal(t, `{"text":"a","integer":1,"float":2.5,"boolean":true}`, serialize(t, s, "s", "float", 2.5), "expected the default output")
+
+	_, err := s.Serialize("s", "float", nil)
+	assert.Error(t, err, "expected an error with a null value")
+}
+
+// TestWithSortedKeys - test if the constant and variable maps are written sorted
+func TestWithSortedKeys(t *testing.T) {
+
+	s := serializer.NewWithOptions(serializer.WithSortedKeys())
+
+	item := CollectionJSON{
+		Mapping: map[string]int{"e": 5, "b": 2, "d": 4, "a": 1, "c": 3},
+		Array:   []float64{},
+	}
+
+	addType(t, s, "constant", item)
+	addType(t, s, "variable", item, "mapping")
+
+	expected := `{"mapping":{"a":1,"b":2,"c":3,"d":4,"e":5},"array":[]}`
+
+	for i := 0; i < 10; i++ {
+		assert.Equal(t, expected, serialize(t, s, "constant"), "expected sorted constant keys")
+		assert.Equal(t, expected, serialize(t, s, "variable", "mapping", item.Mapping), "expected sorted variable keys")
+	}
+}
+
+// TestWithHTMLEscape - test if the HTML characters are escaped only with the option
+func TestWithHTMLEscape(t *testing.T) {
+
+	item := SimpleJSON{Text: "<a&b>"}
+
+	s := serializer.NewWithOptions(serializer.WithHTMLEscape())
+	addType(t, s, "s", item, "text")
+
+	assert.Equal(t, `{"text":"\u003ca\u0026b\u003e","integer":0,"float":0,"boolean":false}`, serialize(t, s, "s"), "expected escaped constant")
+	assert.Equal(t, `{"text":"\u003c/script\u003e","integer":0,"float":0,"boolean":false}`, serialize(t, s, "s", "text", "</script>"), "expected escaped variable")
+
+	s = createSerializer()
+	addType(t, s, "s", item, "text")
+
+	assert.Equal(t, `{"text":"<a&b>","integer":0,"float":0,"boolean":false}`, serialize(t, s, "s"), "expected no escaping by default")
+}
+
+// TestWithFloatFormat - test if the constant and variable floats use the format
+func TestWithFloatFormat(t *testing.T) {
+
+	s := serializer.NewWithOptions(serializer.WithFloatFormat('e', 2))
+
+	addType(t, s, "s", SimpleJSON{Float: 1234.5678}, "float")
+	addType(t, s, "c", CollectionJSON{Array: []float64{0.125}})
+
+	assert.Equal(t, `{"text":"","integer":0,"float":1.23e+03,"boolean":false}`, serialize(t, s, "s"), "expected the formatted default")
+	assert.Equal(t, `{"text":"","integer":0,"float":5.00e-01,"boolean":false}`, serialize(t, s, "s", "float", 0.5), "expected the formatted variable")
+	assert.Equal(t, `{"mapping":{},"array":[1.25e-01]}`, serialize(t, s, "c"), "expected the formatted constant")
+}
+
+// TestWithNullPolicy - test the null values with each policy
+func TestWithNullPolicy(t *testing.T) {
+
+	item := ComplexTypeJSON{
+		Simple: SimpleJSON{Text: "default", Integer: 7},
+		CollectionJSON: CollectionJSON{
+			Mapping: map[string]int{},
+			Array:   []float64{1},
+		},
+	}
+
+	s := serializer.NewWithOptions(serializer.WithNullPolicy(common.NullWrite))
+	addType(t, s, "s", item, "simple.text", "simple.integer", "array")
+
+	result := serialize(t, s, "s", "simple.text", nil, "simple.integer", nil, "array", nil)
+	assert.Equal(t, `{"simple":{"text":null,"integer":null,"float":0,"boolean":false},"mapping":{},"array":[]}`, result, "expected null values")
+
+	s = serializer.NewWithOptions(serializer.WithNullPolicy(common.NullSkip))
+	addType(t, s, "s", item, "simple.text", "simple.integer", "array")
+
+	result = serialize(t, s, "s", "simple.text", nil, "simple.integer", 8, "array", nil)
+	assert.Equal(t, `{"simple":{"text":"default","integer":8,"float":0,"boolean":false},"mapping":{},"array":[1]}`, result, "expected the default values")
+
+	err := s.AddWithOptions("r", item, serializer.Required("simple.text"))
+	if !assert.NoError(t, err, "error adding the mapping") {
+		return
+	}
+
+	_, err = s.Serialize("r", "simple.text", nil)
+	assert.Error(t, err, "expected an error skipping a required variable")
+}
+
+// TestWithPanicPolicy - test the unexpected panics with each policy
+func TestWithPanicPolicy(t *testing.T) {
+
+	items := []*serializer.ArrayItem{nil}
+
+	logger := &recordingLogger{}
+	s := serializer.NewWithOptions(serializer.WithLogger(logger))
+
+	result, err := s.SerializeArray(items...)
+	assert.NoError(t, err, "expected no error by default")
+	assert.Empty(t, result, "expected an empty result")
+	assert.Len(t, logger.messages, 1, "expected the panic to be logged")
+
+	s = serializer.NewWithOptions(serializer.WithLogger(logger), serializer.WithPanicPolicy(common.PanicAsError))
+
+	_, err = s.SerializeArray(items...)
+	assert.Error(t, err, "expected the panic as an error")
+	assert.Len(t, logger.messages, 2, "expected the panic to be logged")
+
+	s = serializer.NewWithOptions(serializer.WithLogger(logger), serializer.WithPanicPolicy(common.PanicPropagate))
+
+	assert.Panics(t, func() { s.SerializeArray(items...) }, "expected the panic to be propagated")
+	assert.Len(t, logger.messages, 2, "expected no log when propagating")
+}
+
+// TestWithMaxOutputSize - test if the outputs bigger than the maximum are rejected
+func TestWithMaxOutputSize(t *testing.T) {
+
+	s := serializer.NewWithOptions(serializer.WithMaxOutputSize(60))
+	addType(t, s, "s", SimpleJSON{}, "text")
+
+	serialize(t, s, "s", "text", "fits")
+
+	_, err := s.Serialize("s", "text", "this text does not fit in the maximum output size")
+	assert.Error(t, err, "expected an error with a big output")
+
+	items := []*serializer.ArrayItem{
+		{Name: "s", Parameters: []interface{}{"text", "a"}},
+		{Name: "s", Parameters: []interface{}{"text", "b"}},
+	}
+
+	_, err = s.SerializeArray(items...)
+	assert.Error(t, err, "expected an error with a big array")
+}
@@ -0,0 +1,63 @@
+package json
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	serializer "github.com/uol/serializer/json"
+)
+
+/**
+* Has unit tests for the parallel JSON array serialization.
+* @author rnojiri
+**/
+
+// TestSerializeArrayParallel - test if the parallel output is the same of the sequential one
+func TestSerializeArrayParallel(t *testing.T) {
+
+	s, items := createEncoderItems(t, 101)
+
+	expected, err := s.SerializeArray(items...)
+	if !assert.NoError(t, err, "error serializing the array") {
+		return
+	}
+
+	for _, workers := range []int{0, 1, 2, 3, 8, 200} {
+
+		result, err := s.SerializeArrayParallel(workers, items...)
+		if !assert.NoError(t, err, "error serializing the array with %d workers", workers) {
+			return
+		}
+
+		assert.Equal(t, expected, result, "expected the same output with %d workers", workers)
+	}
+}
+
+// TestSerializeArrayParallelError - test if the error of the first invalid item is returned
+func TestSerializeArrayParallelError(t *testing.T) {
+
+	s, items := createEncoderItems(t, 20)
+
+	items[13] = &serializer.ArrayItem{Name: "unknown"}
+	items[7] = &serializer.ArrayItem{Name: "s", Parameters: []interface{}{"integer", nil}}
+
+	_, expected := s.SerializeArray(items...)
+	if !assert.Error(t, expected, "expected an error") {
+		return
+	}
+
+	_, err := s.SerializeArrayParallel(4, items...)
+	assert.Equal(t, expected, err, "expected the same error of the sequential serialization")
+}
+
+// TestSerializeArrayParallelPanic - test if an unexpected panic on a shard is returned as an error
+func TestSerializeArrayParallelPanic(t *testing.T) {
+
+	s, items := createEncoderItems(t, 20)
+	items[11] = addPanicItem(t, s)
+
+	result, err := s.SerializeArrayParallel(4, items...)
+	assert.Error(t, err, "expected the panic as an error")
+	assert.Empty(t, result, "expected no output")
+}
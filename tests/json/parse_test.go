@@ -0,0 +1,175 @@
+package json
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	serializer "github.com/uol/serializer/json"
+)
+
+/**
+* Has unit tests for the template aware JSON parsing.
+* @author rnojiri
+**/
+
+// parse - try to parse the data using the named mapping
+func parse(t *testing.T, s *serializer.Serializer, name string, data string) map[string]interface{} {
+
+	result, err := s.Parse(name, []byte(data))
+	if !assert.NoError(t, err, "error parsing the data: %s", data) {
+		panic(err)
+	}
+
+	return result
+}
+
+// TestParseSimple - test parsing back a serialized simple JSON
+func TestParseSimple(t *testing.T) {
+
+	s := createSerializer()
+	addType(t, s, "s", SimpleJSON{Text: "const", Integer: 1}, "text", "float", "boolean")
+
+	result := serialize(t, s, "s",
+		"text", `a "quoted" \ text`,
+		"float", 7.5,
+		"boolean", true,
+	)
+
+	expected := map[string]interface{}{
+		"text":    `a "quoted" \ text`,
+		"float":   7.5,
+		"boolean": true,
+	}
+
+	assert.Equal(t, expected, parse(t, s, "s", result), "expected same variables")
+}
+
+// TestParseComplexType - test parsing back variables from sub objects, maps and arrays
+func TestParseComplexType(t *testing.T) {
+
+	newType := ComplexTypeJSON{
+		Simple: SimpleJSON{
+			Text: "complex",
+		},
+		CollectionJSON: CollectionJSON{
+			Mapping: map[string]int{"1": 1},
+			Array:   []float64{1.0, 2.0, 3.0},
+		},
+	}
+
+	s := createSerializer()
+	addType(t, s, "s", newType, "simple.integer", "mapping.1", "array[1]")
+
+	result := serialize(t, s, "s",
+		"simple.integer", -7,
+		"mapping.1", 10,
+		"array[1]", -2.5,
+	)
+
+	expected := map[string]interface{}{
+		"simple.integer": int64(-7),
+		"mapping.1":      int64(10),
+		"array[1]":       -2.5,
+	}
+
+	assert.Equal(t, expected, parse(t, s, "s", result), "expected same variables")
+}
+
+// TestParseCollectionVariables - test parsing back map and array variables
+func TestParseCollectionVariables(t *testing.T) {
+
+	s := createSerializer()
+	addType(t, s, "s", CollectionJSON{}, "mapping", "array")
+
+	result := serialize(t, s, "s",
+		"mapping", map[string]int{"a": 1, "b": 2},
+		"array", []float64{4.5, 5.5},
+	)
+
+	expected := map[string]interface{}{
+		"mapping": map[string]interface{}{"a": 1.0, "b": 2.0},
+		"array":   []interface{}{4.5, 5.5},
+	}
+
+	assert.Equal(t, expected, parse(t, s, "s", result), "expected same variables")
+}
+
+// TestParseFallback - test parsing data which does not match the template constant parts
+func TestParseFallback(t *testing.T) {
+
+	s := createSerializer()
+	addType(t, s, "s", SimpleJSON{Text: "const"}, "integer", "boolean")
+
+	data := `{ "boolean": true, "integer": 42, "text": "other", "float": 1 }`
+
+	expected := map[string]interface{}{
+		"integer": int64(42),
+		"boolean": true,
+	}
+
+	assert.Equal(t, expected, parse(t, s, "s", data), "expected same variables")
+}
+
+// TestParseErrors - test parsing invalid data or with an unknown mapping
+func TestParseErrors(t *testing.T) {
+
+	s := createSerializer()
+	addType(t, s, "s", SimpleJSON{}, "integer")
+
+	_, err := s.Parse("s", []byte(`{"text":"missing the variable"}`))
+	assert.Error(t, err, "expected an error when the variable is missing")
+
+	_, err = s.Parse("s", []byte(`{"integer":`))
+	assert.Error(t, err, "expected an error parsing invalid json")
+
+	_, err = s.Parse("unknown", []byte(`{}`))
+	assert.Error(t, err, "expected an error with an unknown mapping")
+}
+
+// TestParseInto - test parsing back the variables to a typed struct
+func TestParseInto(t *testing.T) {
+
+	p := serializer.NumberPoint{
+		Point: serializer.Point{
+			Metric:    "metric1",
+			Timestamp: time.Now().Unix(),
+			Tags: map[string]string{
+				"ksid": "keyset",
+				"host": "here",
+			},
+		},
+		Value: 1.0,
+	}
+
+	s := createSerializer()
+	addType(t, s, "c", p, "metric", "value", "tags.host")
+
+	result := serialize(t, s, "c",
+		"metric", "metric2",
+		"value", 100.5,
+		"tags.host", "loghost",
+	)
+
+	actual := serializer.NumberPoint{}
+	err := s.ParseInto("c", []byte(result), &actual)
+	if !assert.NoError(t, err, "error parsing into the struct") {
+		return
+	}
+
+	expected := serializer.NumberPoint{
+		Point: serializer.Point{
+			Metric: "metric2",
+			Tags: map[string]string{
+				"host": "loghost",
+			},
+		},
+		Value: 100.5,
+	}
+
+	assert.Equal(t, expected, actual, "expected only the variables to be set")
+
+	err = s.ParseInto("c", []byte(result), actual)
+	assert.Error(t, err, "expected an error with a non pointer destination")
+}
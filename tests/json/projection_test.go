@@ -0,0 +1,108 @@
+package json
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	serializer "github.com/uol/serializer/json"
+)
+
+/**
+* Has unit tests for the projections.
+* @author rnojiri
+**/
+
+// createProjectionSerializer - creates a serializer with the result mapping and its optional version
+func createProjectionSerializer(t *testing.T) *serializer.Serializer {
+
+	s := createSerializer()
+
+	err := s.AddWithOptions("result", createResult(), serializer.Variables("id", "error.code", "tags.host"), serializer.Indent("", " "))
+	if !assert.NoError(t, err, "error adding the mapping") {
+		panic(err)
+	}
+
+	err = s.AddWithOptions("optional", createResult(), serializer.Variables("id", "error.code"), serializer.Optional("error"))
+	if !assert.NoError(t, err, "error adding the mapping") {
+		panic(err)
+	}
+
+	return s
+}
+
+// TestAddProjection - test the precompiled projections
+func TestAddProjection(t *testing.T) {
+
+	s := createProjectionSerializer(t)
+
+	err := s.AddProjection("result", "summary", "id", "tags")
+	if !assert.NoError(t, err, "error adding the projection") {
+		return
+	}
+
+	assert.Equal(t, `{"id":1,"tags":{"host":"a"}}`, serialize(t, s, "summary"), "expected only the projected properties")
+	assert.Equal(t, `{"id":2,"tags":{"host":"b"}}`, serialize(t, s, "summary", "id", 2, "error.code", 1, "tags.host", "b"), "expected the variables out of the projection ignored")
+
+	_, err = s.Serialize("summary", "unknown", 1)
+	assert.Error(t, err, "expected an error with an unknown variable")
+
+	result, err := s.SerializeIndent("summary", "id", 3)
+	if assert.NoError(t, err, "error serializing the indented projection") {
+		assert.Equal(t, "{\n \"id\": 3,\n \"tags\": {\n  \"host\": \"a\"\n }\n}", result, "expected the indented projection")
+	}
+
+	values, err := s.Parse("summary", []byte(`{"id":4,"tags":{"host":"c"}}`))
+	if assert.NoError(t, err, "error parsing the projection") {
+		assert.Equal(t, map[string]interface{}{"id": int64(4), "tags.host": "c"}, values, "expected the projected variables")
+	}
+
+	assert.Equal(t, []interface{}{"id", "tags"}, schema(t, s, "summary")["required"], "expected only the projected properties in the schema")
+	assert.Equal(t, `{"id":1,"error":{"code":500,"message":"100% failed"},"elapsed":10,"tags":{"host":"a"}}`, serialize(t, s, "result"), "expected the original mapping unchanged")
+}
+
+// TestSerializeProjected - test the projections compiled on serialization, including the sub-properties
+func TestSerializeProjected(t *testing.T) {
+
+	s := createProjectionSerializer(t)
+
+	result, err := s.SerializeProjected("result", []string{"error.message", "elapsed"}, "id", 5)
+	if assert.NoError(t, err, "error serializing the projection") {
+		assert.Equal(t, `{"error":{"message":"100% failed"},"elapsed":10}`, result, "expected the sub-property and the constant")
+	}
+
+	result, err = s.SerializeProjected("result", []string{"elapsed", "error.code"}, "error.code", 404)
+	if assert.NoError(t, err, "error serializing the projection") {
+		assert.Equal(t, `{"error":{"code":404},"elapsed":10}`, result, "expected the properties in the mapping order")
+	}
+
+	_, err = s.SerializeProjected("result", []string{"unknown"})
+	assert.Error(t, err, "expected an error with an unknown property")
+
+	_, err = s.SerializeProjected("result", nil)
+	assert.Error(t, err, "expected an error without properties")
+
+	_, err = s.SerializeProjected("unknown", []string{"id"})
+	assert.Error(t, err, "expected an error with an unknown mapping")
+}
+
+// TestProjectedOptionalSections - test the optional sections inside the projections
+func TestProjectedOptionalSections(t *testing.T) {
+
+	s := createProjectionSerializer(t)
+
+	result, err := s.SerializeProjected("optional", []string{"id", "error"})
+	if assert.NoError(t, err, "error serializing the projection") {
+		assert.Equal(t, `{"id":1}`, result, "expected the optional section omitted")
+	}
+
+	result, err = s.SerializeProjected("optional", []string{"id", "error"}, "error.code", 1)
+	if assert.NoError(t, err, "error serializing the projection") {
+		assert.Equal(t, `{"id":1,"error":{"code":1,"message":"100% failed"}}`, result, "expected the optional section")
+	}
+
+	result, err = s.SerializeProjected("optional", []string{"error.message", "id"}, "error.code", 1)
+	if assert.NoError(t, err, "error serializing the projection") {
+		assert.Equal(t, `{"id":1}`, result, "expected the optional section without projected variables removed")
+	}
+}
@@ -0,0 +1,85 @@
+package json
+
+import (
+	"encoding/base64"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	serializer "github.com/uol/serializer/json"
+)
+
+/**
+* Has unit tests for the byte slices and raw JSON fragments.
+* @author rnojiri
+**/
+
+type BytesJSON struct {
+	Data     []byte         `json:"data"`
+	Fragment serializer.Raw `json:"fragment"`
+	Text     string         `json:"text"`
+}
+
+// TestBytesAsBase64 - test if the byte slices are written as base64 strings
+func TestBytesAsBase64(t *testing.T) {
+
+	s := createSerializer()
+
+	item := BytesJSON{Data: []byte("hello"), Fragment: serializer.Raw(`{"a":[1,2]}`)}
+	addType(t, s, "constant", item)
+	addType(t, s, "variable", item, "data")
+
+	expected := `{"data":"aGVsbG8=","fragment":{"a":[1,2]},"text":""}`
+	assert.Equal(t, expected, serialize(t, s, "constant"), "expected a base64 constant")
+	assert.Equal(t, expected, serialize(t, s, "variable"), "expected a base64 default")
+
+	data := []byte{0, 255, 10, 37}
+	expected = `{"data":"` + base64.StdEncoding.EncodeToString(data) + `","fragment":{"a":[1,2]},"text":""}`
+	assert.Equal(t, expected, serialize(t, s, "variable", "data", data), "expected a base64 variable")
+
+	assert.Equal(t, `{"data":null,"fragment":null,"text":""}`, marshal(t, BytesJSON{}), "expected null slices")
+}
+
+// TestRawFragments - test if the raw fragments are inserted as is
+func TestRawFragments(t *testing.T) {
+
+	s := createSerializer()
+	addType(t, s, "s", BytesJSON{Data: []byte{}}, "fragment")
+
+	result := serialize(t, s, "s", "fragment", serializer.Raw(`{"nested":{"values":[1,"%d",true]}}`))
+	assert.Equal(t, `{"data":"","fragment":{"nested":{"values":[1,"%d",true]}},"text":""}`, result, "expected the fragment as is")
+
+	assert.Equal(t, `{"data":"","fragment":null,"text":""}`, serialize(t, s, "s"), "expected a null fragment")
+
+	result = serialize(t, s, "s", "fragment", serializer.Raw(`{invalid`))
+	assert.Equal(t, `{"data":"","fragment":{invalid,"text":""}`, result, "expected no validation by default")
+
+	s = serializer.NewWithOptions(serializer.WithRawValidation())
+	addType(t, s, "s", BytesJSON{Data: []byte{}}, "fragment")
+
+	_, err := s.Serialize("s", "fragment", serializer.Raw(`{invalid`))
+	assert.Error(t, err, "expected an error with an invalid fragment")
+
+	err = s.Add("invalid", BytesJSON{Fragment: serializer.Raw(`[1,`)})
+	assert.Error(t, err, "expected an error with an invalid constant fragment")
+}
+
+// TestPercentConstants - test if the constants with the verb symbol are written as is
+func TestPercentConstants(t *testing.T) {
+
+	s := createSerializer()
+
+	item := CollectionJSON{
+		Mapping: map[string]int{"100%": 1},
+		Array:   []float64{},
+	}
+
+	addType(t, s, "s", SimpleJSON{Text: "100%d %s"}, "integer")
+	addType(t, s, "c", item, "array")
+
+	assert.Equal(t, `{"text":"100%d %s","integer":7,"float":0,"boolean":false}`, serialize(t, s, "s", "integer", 7), "expected the verb symbol as is")
+	assert.Equal(t, `{"mapping":{"100%":1},"array":[2]}`, serialize(t, s, "c", "array", []float64{2}), "expected the verb symbol as is")
+
+	variables := parse(t, s, "s", `{"text":"100%d %s","integer":7,"float":0,"boolean":false}`)
+	assert.Equal(t, int64(7), variables["integer"], "expected the variable parsed")
+}
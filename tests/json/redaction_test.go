@@ -0,0 +1,223 @@
+package json
+
+import (
+	"fmt"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	serializer "github.com/uol/serializer/json"
+)
+
+/**
+* Has unit tests for the sensitive properties redaction.
+* @author rnojiri
+**/
+
+type ProfileJSON struct {
+	Phone string `json:"phone,redact"`
+	Age   int    `json:"age"`
+}
+
+type CredentialsJSON struct {
+	User    string      `json:"user"`
+	Token   string      `json:"token,redact"`
+	Email   string      `json:"email"`
+	Scopes  []string    `json:"scopes"`
+	Profile ProfileJSON `json:"profile"`
+}
+
+// createCredentials - creates the mapped credentials
+func createCredentials() CredentialsJSON {
+
+	return CredentialsJSON{
+		User:    "user",
+		Token:   "secret",
+		Email:   "user@domain.com",
+		Scopes:  []string{"read"},
+		Profile: ProfileJSON{Phone: "555-0100", Age: 30},
+	}
+}
+
+// addCredentials - adds the credentials mapping with the email and the scopes redacted too
+func addCredentials(t *testing.T, s *serializer.Serializer) {
+
+	err := s.AddWithOptions("credentials", createCredentials(), serializer.Variables("token", "scopes", "profile.age"), serializer.Redact("email", "scopes"))
+	if !assert.NoError(t, err, "error adding the mapping") {
+		panic(err)
+	}
+}
+
+// TestRedactedView - test if only the redacted view masks the constants and the variables
+func TestRedactedView(t *testing.T) {
+
+	s := createSerializer()
+	addCredentials(t, s)
+
+	assert.Equal(t, `{"user":"user","token":"secret","email":"user@domain.com","scopes":["read"],"profile":{"phone":"555-0100","age":30}}`, serialize(t, s, "credentials"), "expected the values in clear")
+
+	expected := `{"user":"user","token":"***","email":"***","scopes":"***","profile":{"phone":"***","age":%d}}`
+
+	result, err := s.Redacted().Serialize("credentials")
+	if assert.NoError(t, err, "error serializing the redacted view") {
+		assert.Equal(t, fmt.Sprintf(expected, 30), result, "expected the masked defaults and constants")
+	}
+
+	result, err = s.Redacted().Serialize("credentials", "token", "100% %s", "scopes", []string{"write"}, "profile.age", 31)
+	if assert.NoError(t, err, "error serializing the redacted view") {
+		assert.Equal(t, fmt.Sprintf(expected, 31), result, "expected the masked variables")
+	}
+
+	result, err = s.Redacted().SerializeArray(&serializer.ArrayItem{Name: "credentials"}, &serializer.ArrayItem{Name: "credentials", Parameters: []interface{}{"profile.age", 1}})
+	if assert.NoError(t, err, "error serializing the redacted array") {
+		assert.Equal(t, "["+fmt.Sprintf(expected, 30)+","+fmt.Sprintf(expected, 1)+"]", result, "expected the masked items")
+	}
+
+	assert.Equal(t, `{"user":"user","token":"x","email":"user@domain.com","scopes":["read"],"profile":{"phone":"555-0100","age":30}}`, serialize(t, s, "credentials", "token", "x"), "expected the values in clear")
+}
+
+// TestRedactionFunctions - test the hash and the custom masks
+func TestRedactionFunctions(t *testing.T) {
+
+	s := serializer.NewWithOptions(serializer.WithRedaction(serializer.HashValue))
+	addType(t, s, "constant", ProfileJSON{Phone: "555-0100"})
+	addType(t, s, "variable", ProfileJSON{}, "phone")
+
+	expected := `{"phone":"` + serializer.HashValue(`"555-0100"`) + `","age":0}`
+
+	result, err := s.Redacted().Serialize("constant")
+	if assert.NoError(t, err, "error serializing the redacted view") {
+		assert.Equal(t, expected, result, "expected the hashed constant")
+	}
+
+	result, err = s.Redacted().Serialize("variable", "phone", "555-0100")
+	if assert.NoError(t, err, "error serializing the redacted view") {
+		assert.Equal(t, expected, result, "expected the same hash of the variable")
+	}
+
+	s = serializer.NewWithOptions(serializer.WithRedaction(func(value string) string {
+		return "<" + strconv.Itoa(len(value)) + ` "chars">`
+	}))
+	addType(t, s, "variable", ProfileJSON{}, "phone")
+
+	result, err = s.Redacted().Serialize("variable", "phone", "12345")
+	if assert.NoError(t, err, "error serializing the redacted view") {
+		assert.Equal(t, `{"phone":"<7 \"chars\">","age":0}`, result, "expected the escaped custom mask")
+	}
+}
+
+// TestAlwaysRedact - test if all serializations are masked with the option
+func TestAlwaysRedact(t *testing.T) {
+
+	s := serializer.NewWithOptions(serializer.WithAlwaysRedact())
+
+	err := s.AddWithOptions("credentials", createCredentials(), serializer.Variables("token"), serializer.Indent("", ""))
+	if !assert.NoError(t, err, "error adding the mapping") {
+		return
+	}
+
+	assert.Equal(t, `{"user":"user","token":"***","email":"user@domain.com","scopes":["read"],"profile":{"phone":"***","age":30}}`, serialize(t, s, "credentials", "token", "t"), "expected the masked properties")
+
+	result, err := s.SerializeIndent("credentials", "token", "t")
+	if assert.NoError(t, err, "error serializing the indented template") {
+		assert.Equal(t, "{\n\"user\": \"user\",\n\"token\": \"***\",\n\"email\": \"user@domain.com\",\n\"scopes\": [\n\"read\"\n],\n\"profile\": {\n\"phone\": \"***\",\n\"age\": 30\n}\n}", result, "expected the masked indented properties")
+	}
+
+	result, err = s.Marshal(CredentialsJSON{Token: "t"})
+	if assert.NoError(t, err, "error marshalling") {
+		assert.Equal(t, `{"user":"","token":"***","email":"","scopes":null,"profile":{"phone":"***","age":0}}`, result, "expected the masked marshalled fields")
+	}
+}
+
+// TestRedactedMarshal - test the marshalled structs in the redacted view
+func TestRedactedMarshal(t *testing.T) {
+
+	s := createSerializer()
+
+	item := createCredentials()
+
+	result, err := s.Redacted().Marshal(item)
+	if assert.NoError(t, err, "error marshalling the redacted view") {
+		assert.Equal(t, `{"user":"user","token":"***","email":"user@domain.com","scopes":["read"],"profile":{"phone":"***","age":30}}`, result, "expected the masked tagged fields")
+	}
+
+	result, err = s.Marshal(item)
+	if assert.NoError(t, err, "error marshalling") {
+		assert.Equal(t, `{"user":"user","token":"secret","email":"user@domain.com","scopes":["read"],"profile":{"phone":"555-0100","age":30}}`, result, "expected the fields in clear")
+	}
+}
+
+// TestRedactedProjectionsAndSections - test the redaction of the projections and the optional sections
+func TestRedactedProjectionsAndSections(t *testing.T) {
+
+	s := createSerializer()
+	addCredentials(t, s)
+
+	result, err := s.Redacted().SerializeProjected("credentials", []string{"token", "profile.age"}, "token", "t")
+	if assert.NoError(t, err, "error serializing the redacted projection") {
+		assert.Equal(t, `{"token":"***","profile":{"age":30}}`, result, "expected the masked projection")
+	}
+
+	err = s.AddProjection("credentials", "contact", "email", "user")
+	if assert.NoError(t, err, "error adding the projection") {
+		result, err = s.Redacted().Serialize("contact")
+		if assert.NoError(t, err, "error serializing the redacted projection") {
+			assert.Equal(t, `{"user":"user","email":"***"}`, result, "expected the masked projection")
+		}
+	}
+
+	err = s.AddWithOptions("optional", createCredentials(), serializer.Variables("token"), serializer.Optional("token"))
+	if assert.NoError(t, err, "error adding the mapping") {
+
+		result, err = s.Redacted().Serialize("optional")
+		if assert.NoError(t, err, "error serializing the redacted view") {
+			assert.Equal(t, `{"user":"user","email":"user@domain.com","scopes":["read"],"profile":{"phone":"***","age":30}}`, result, "expected the optional section omitted")
+		}
+
+		result, err = s.Redacted().Serialize("optional", "token", "t")
+		if assert.NoError(t, err, "error serializing the redacted view") {
+			assert.Equal(t, `{"user":"user","token":"***","email":"user@domain.com","scopes":["read"],"profile":{"phone":"***","age":30}}`, result, "expected the masked optional section")
+		}
+	}
+}
+
+// TestRedactErrors - test the invalid redacted properties
+func TestRedactErrors(t *testing.T) {
+
+	s := createSerializer()
+
+	err := s.AddWithOptions("unknown", createCredentials(), serializer.Redact("unknown"))
+	assert.Error(t, err, "expected an error with an unknown property")
+
+	err = s.AddWithOptions("inner", createCredentials(), serializer.Variables("profile.age"), serializer.Redact("profile"))
+	assert.Error(t, err, "expected an error with a redacted property containing variables")
+
+	type DynamicJSON struct {
+		*ProfileJSON
+		Token string `json:"token,redact"`
+	}
+
+	_, err = s.Redacted().Marshal(DynamicJSON{Token: "t"})
+	assert.Error(t, err, "expected an error with a dynamic type")
+}
+
+// TestRedactedMarshalPointer - test the marshalled pointers in the redacted view
+func TestRedactedMarshalPointer(t *testing.T) {
+
+	s := createSerializer()
+
+	item := createCredentials()
+
+	result, err := s.Redacted().Marshal(&item)
+	if assert.NoError(t, err, "error marshalling the redacted view") {
+		assert.Equal(t, `{"user":"user","token":"***","email":"user@domain.com","scopes":["read"],"profile":{"phone":"***","age":30}}`, result, "expected the masked tagged fields")
+	}
+
+	var nilPointer *CredentialsJSON
+
+	result, err = s.Redacted().Marshal(nilPointer)
+	if assert.NoError(t, err, "error marshalling the redacted view") {
+		assert.Equal(t, "null", result, "expected null with a nil pointer")
+	}
+}
@@ -0,0 +1,112 @@
+package json
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	serializer "github.com/uol/serializer/json"
+)
+
+/**
+* Has unit tests for the JSON Schema export.
+* @author rnojiri
+**/
+
+// schema - try to export the schema of the named mapping
+func schema(t *testing.T, s *serializer.Serializer, name string) map[string]interface{} {
+
+	result, err := s.Schema(name)
+	if !assert.NoError(t, err, "error exporting the schema: %s", name) {
+		panic(err)
+	}
+
+	parsed := map[string]interface{}{}
+	err = json.Unmarshal(result, &parsed)
+	if !assert.NoError(t, err, "error unmarshalling the schema: %s", string(result)) {
+		panic(err)
+	}
+
+	return parsed
+}
+
+// TestSchemaSimple - test the schema of a simple JSON with constants and variables
+func TestSchemaSimple(t *testing.T) {
+
+	s := createSerializer()
+	addType(t, s, "s", SimpleJSON{Text: "const", Integer: 10, Float: 1.5}, "boolean", "float")
+
+	expected := map[string]interface{}{
+		"$schema": "https://json-schema.org/draft/2020-12/schema",
+		"title":   "s",
+		"type":    "object",
+		"properties": map[string]interface{}{
+			"text":    map[string]interface{}{"const": "const"},
+			"integer": map[string]interface{}{"const": 10.0},
+			"float":   map[string]interface{}{"type": "number"},
+			"boolean": map[string]interface{}{"type": "boolean"},
+		},
+		"required":             []interface{}{"text", "integer", "float", "boolean"},
+		"additionalProperties": false,
+	}
+
+	assert.Equal(t, expected, schema(t, s, "s"), "expected same schema")
+}
+
+// TestSchemaComplexType - test the schema of nested objects, maps and arrays
+func TestSchemaComplexType(t *testing.T) {
+
+	newType := ComplexTypeJSON{
+		Simple: SimpleJSON{Text: "complex"},
+		CollectionJSON: CollectionJSON{
+			Mapping: map[string]int{"1": 1},
+			Array:   []float64{1.0, 2.0},
+		},
+	}
+
+	s := createSerializer()
+	addType(t, s, "s", newType, "simple.text", "mapping.1", "array[1]")
+
+	properties := schema(t, s, "s")["properties"].(map[string]interface{})
+
+	simple := properties["simple"].(map[string]interface{})
+	assert.Equal(t, "object", simple["type"], "expected an object")
+	assert.Equal(t, map[string]interface{}{"type": "string"}, simple["properties"].(map[string]interface{})["text"], "expected a string variable")
+
+	expectedMapping := map[string]interface{}{
+		"type": "object",
+		"properties": map[string]interface{}{
+			"1": map[string]interface{}{"type": "integer"},
+		},
+		"required":             []interface{}{"1"},
+		"additionalProperties": false,
+	}
+	assert.Equal(t, expectedMapping, properties["mapping"], "expected same map schema")
+
+	expectedArray := map[string]interface{}{
+		"type": "array",
+		"prefixItems": []interface{}{
+			map[string]interface{}{"const": 1.0},
+			map[string]interface{}{"type": "number"},
+		},
+		"items":    false,
+		"minItems": 2.0,
+	}
+	assert.Equal(t, expectedArray, properties["array"], "expected same array schema")
+}
+
+// TestSchemaCollectionVariables - test the schema of map and array variables
+func TestSchemaCollectionVariables(t *testing.T) {
+
+	s := createSerializer()
+	addType(t, s, "s", CollectionJSON{}, "mapping", "array")
+
+	properties := schema(t, s, "s")["properties"].(map[string]interface{})
+
+	assert.Equal(t, map[string]interface{}{"type": "object", "additionalProperties": map[string]interface{}{"type": "integer"}}, properties["mapping"], "expected same map schema")
+	assert.Equal(t, map[string]interface{}{"type": "array", "items": map[string]interface{}{"type": "number"}}, properties["array"], "expected same array schema")
+
+	_, err := s.Schema("unknown")
+	assert.Error(t, err, "expected an error with an unknown mapping")
+}
@@ -0,0 +1,147 @@
+package json
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	serializer "github.com/uol/serializer/json"
+)
+
+/**
+* Has unit tests for the optional sections.
+* @author rnojiri
+**/
+
+type FailureJSON struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+type ResultJSON struct {
+	ID      int               `json:"id"`
+	Error   FailureJSON       `json:"error"`
+	Elapsed int               `json:"elapsed"`
+	Tags    map[string]string `json:"tags"`
+}
+
+type LeadingJSON struct {
+	Warning string      `json:"warning"`
+	Error   FailureJSON `json:"error"`
+	Note    string      `json:"note"`
+	ID      int         `json:"id"`
+}
+
+// createResult - creates the mapped result
+func createResult() ResultJSON {
+
+	return ResultJSON{
+		ID:      1,
+		Error:   FailureJSON{Code: 500, Message: "100% failed"},
+		Elapsed: 10,
+		Tags:    map[string]string{"host": "a"},
+	}
+}
+
+// TestOptionalSection - test if the optional sub-object is omitted when its variables are not supplied
+func TestOptionalSection(t *testing.T) {
+
+	s := createSerializer()
+
+	err := s.AddWithOptions("result", createResult(), serializer.Variables("id", "error.code", "error.message", "tags.host"), serializer.Optional("error", "tags.host"))
+	if !assert.NoError(t, err, "error adding the mapping") {
+		return
+	}
+
+	assert.Equal(t, `{"id":1,"elapsed":10,"tags":{}}`, serialize(t, s, "result"), "expected the optional sections omitted")
+	assert.Equal(t, `{"id":2,"elapsed":10,"tags":{}}`, serialize(t, s, "result", "id", 2), "expected the optional sections omitted")
+
+	result := serialize(t, s, "result", "error.code", 404)
+	assert.Equal(t, `{"id":1,"error":{"code":404,"message":"100% failed"},"elapsed":10,"tags":{}}`, result, "expected the error section with the default message")
+
+	result = serialize(t, s, "result", "error.code", serializer.Absent, "error.message", "bad", "tags.host", "b")
+	assert.Equal(t, `{"id":1,"error":{"code":500,"message":"bad"},"elapsed":10,"tags":{"host":"b"}}`, result, "expected all sections")
+
+	result = serialize(t, s, "result", "error.code", serializer.Absent, "error.message", serializer.Absent)
+	assert.Equal(t, `{"id":1,"elapsed":10,"tags":{}}`, result, "expected the absent section omitted")
+}
+
+// TestOptionalCommas - test the commas around the optional properties in any position
+func TestOptionalCommas(t *testing.T) {
+
+	s := createSerializer()
+
+	item := LeadingJSON{Warning: "w", Note: "n", ID: 1}
+
+	err := s.AddWithOptions("leading", item, serializer.Variables("warning", "error.code", "note", "id"), serializer.Optional("warning", "error", "note"))
+	if !assert.NoError(t, err, "error adding the mapping") {
+		return
+	}
+
+	err = s.AddWithOptions("all", item, serializer.Variables("warning", "error.code", "note", "id"), serializer.Optional("warning", "error", "note", "id"))
+	if !assert.NoError(t, err, "error adding the mapping") {
+		return
+	}
+
+	assert.Equal(t, `{"id":1}`, serialize(t, s, "leading"), "expected only the mandatory property")
+	assert.Equal(t, `{"warning":"x","id":1}`, serialize(t, s, "leading", "warning", "x"), "expected the first property")
+	assert.Equal(t, `{"error":{"code":1,"message":""},"id":1}`, serialize(t, s, "leading", "error.code", 1), "expected the second property")
+	assert.Equal(t, `{"warning":"x","note":"y","id":1}`, serialize(t, s, "leading", "warning", "x", "note", "y"), "expected the first and third properties")
+
+	assert.Equal(t, `{}`, serialize(t, s, "all"), "expected an empty object")
+	assert.Equal(t, `{"note":"y"}`, serialize(t, s, "all", "note", "y"), "expected only the third property")
+	assert.Equal(t, `{"warning":"x","id":2}`, serialize(t, s, "all", "warning", "x", "id", 2), "expected the first and the last properties")
+	assert.Equal(t, `{"warning":"w","error":{"code":0,"message":""},"note":"n","id":1}`, serialize(t, s, "all", "warning", "w", "error.code", 0, "note", "n", "id", 1), "expected all properties")
+}
+
+// TestOptionalIndentAndParse - test the optional sections in the indented template and parsing them back
+func TestOptionalIndentAndParse(t *testing.T) {
+
+	s := createSerializer()
+
+	err := s.AddWithOptions("result", createResult(), serializer.Variables("id", "error.code"), serializer.Optional("error"), serializer.Indent("", "  "))
+	if !assert.NoError(t, err, "error adding the mapping") {
+		return
+	}
+
+	result, err := s.SerializeIndent("result", "id", 3)
+	if assert.NoError(t, err, "error serializing the indented template") {
+		assert.Equal(t, "{\n  \"id\": 3,\n  \"elapsed\": 10,\n  \"tags\": {\n    \"host\": \"a\"\n  }\n}", result, "expected the indented error section omitted")
+	}
+
+	result, err = s.SerializeIndent("result", "error.code", 1)
+	if assert.NoError(t, err, "error serializing the indented template") {
+		assert.Equal(t, "{\n  \"id\": 1,\n  \"error\": {\n    \"code\": 1,\n    \"message\": \"100% failed\"\n  },\n  \"elapsed\": 10,\n  \"tags\": {\n    \"host\": \"a\"\n  }\n}", result, "expected the indented error section")
+	}
+
+	values, err := s.Parse("result", []byte(serialize(t, s, "result", "id", 7)))
+	if assert.NoError(t, err, "error parsing the omitted section") {
+		assert.Equal(t, map[string]interface{}{"id": int64(7)}, values, "expected only the supplied variable")
+	}
+
+	values, err = s.Parse("result", []byte(serialize(t, s, "result", "error.code", 9)))
+	if assert.NoError(t, err, "error parsing the section") {
+		assert.Equal(t, map[string]interface{}{"id": int64(1), "error.code": int64(9)}, values, "expected all variables")
+	}
+
+	required := schema(t, s, "result")["required"]
+	assert.Equal(t, []interface{}{"id", "elapsed", "tags"}, required, "expected the optional property not required")
+}
+
+// TestOptionalErrors - test the invalid optional properties
+func TestOptionalErrors(t *testing.T) {
+
+	s := createSerializer()
+
+	err := s.AddWithOptions("unknown", createResult(), serializer.Variables("id"), serializer.Optional("unknown"))
+	assert.Error(t, err, "expected an error with an unknown property")
+
+	err = s.AddWithOptions("constant", createResult(), serializer.Variables("id"), serializer.Optional("error"))
+	assert.Error(t, err, "expected an error with an optional property without variables")
+
+	err = s.AddWithOptions("required", createResult(), serializer.Required("error.code"), serializer.Optional("error"))
+	if assert.NoError(t, err, "error adding the mapping") {
+		_, err = s.Serialize("required", "error.code", serializer.Absent)
+		assert.Error(t, err, "expected an error with an absent required variable")
+	}
+}
@@ -0,0 +1,122 @@
+package json
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	serializer "github.com/uol/serializer/json"
+	common "github.com/uol/serializer/serializer"
+)
+
+/**
+* Has unit tests for the interface{} trees.
+* @author rnojiri
+**/
+
+type AttributesJSON struct {
+	ID         int                    `json:"id"`
+	Attributes map[string]interface{} `json:"attributes"`
+	Extra      interface{}            `json:"extra"`
+}
+
+type LabelJSON struct {
+	Name  string  `json:"name"`
+	Score float64 `json:"score"`
+	SimpleJSON
+	hidden string
+}
+
+// createTree - creates a dynamic tree with all kinds of values
+func createTree() map[string]interface{} {
+
+	label := &LabelJSON{Name: "<b>", Score: 0.5, SimpleJSON: SimpleJSON{Text: `a "quoted" text`}}
+
+	return map[string]interface{}{
+		"name":    "tree & co",
+		"count":   3,
+		"ratio":   1.25,
+		"enabled": true,
+		"nothing": nil,
+		"nested": map[string]interface{}{
+			"list":  []interface{}{1, "two", 3.5, false, nil, []int{4, 5}},
+			"empty": map[string]interface{}{},
+		},
+		"label":  label,
+		"labels": []*LabelJSON{label, nil},
+	}
+}
+
+// stdMarshal - marshals using the standard library
+func stdMarshal(t *testing.T, item interface{}) string {
+
+	result, err := json.Marshal(item)
+	if !assert.NoError(t, err, "error marshalling with the standard library") {
+		panic(err)
+	}
+
+	return string(result)
+}
+
+// TestTreeConstants - test if the trees are written like the standard library does
+func TestTreeConstants(t *testing.T) {
+
+	s := serializer.NewWithOptions(serializer.WithSortedKeys(), serializer.WithHTMLEscape())
+
+	item := AttributesJSON{ID: 1, Attributes: createTree(), Extra: []interface{}{map[string]interface{}{"a": 1}}}
+	addType(t, s, "s", item)
+
+	assert.Equal(t, stdMarshal(t, item), serialize(t, s, "s"), "expected the same output of the standard library")
+}
+
+// TestTreeVariables - test if the trees are written as variables
+func TestTreeVariables(t *testing.T) {
+
+	s := serializer.NewWithOptions(serializer.WithSortedKeys(), serializer.WithHTMLEscape(), serializer.WithNullPolicy(common.NullWrite))
+	addType(t, s, "s", AttributesJSON{Attributes: map[string]interface{}{"default": "value"}}, "id", "attributes", "extra")
+	addType(t, s, "v", AttributesJSON{Attributes: map[string]interface{}{"default": "value"}}, "attributes.default")
+
+	tree := createTree()
+	expected := AttributesJSON{ID: 2, Attributes: tree, Extra: tree["nested"]}
+
+	assert.Equal(t, stdMarshal(t, expected), serialize(t, s, "s", "id", 2, "attributes", tree, "extra", tree["nested"]), "expected the trees as variables")
+
+	for _, extra := range []interface{}{"text", 10, []string{"a"}, map[string]int{"b": 1}, nil} {
+
+		expected = AttributesJSON{Attributes: map[string]interface{}{"default": "value"}, Extra: extra}
+		assert.Equal(t, stdMarshal(t, expected), serialize(t, s, "s", "extra", extra), "expected any value in the interface variable")
+	}
+
+	expected = AttributesJSON{Attributes: map[string]interface{}{"default": []int{1, 2}}}
+	assert.Equal(t, stdMarshal(t, expected), serialize(t, s, "v", "attributes.default", []int{1, 2}), "expected a tree in the interface map value")
+}
+
+// TestTreeMarshal - test if the interface fields are marshalled as trees
+func TestTreeMarshal(t *testing.T) {
+
+	s := serializer.NewWithOptions(serializer.WithSortedKeys(), serializer.WithHTMLEscape())
+
+	item := AttributesJSON{ID: 3, Attributes: createTree(), Extra: createTree()}
+
+	result, err := s.Marshal(item)
+	if assert.NoError(t, err, "error marshalling") {
+		assert.Equal(t, stdMarshal(t, item), result, "expected the same output of the standard library")
+	}
+}
+
+// TestTreeErrors - test the unsupported and cyclic trees
+func TestTreeErrors(t *testing.T) {
+
+	s := createSerializer()
+	addType(t, s, "s", AttributesJSON{}, "attributes", "extra")
+
+	cyclic := map[string]interface{}{}
+	cyclic["self"] = cyclic
+
+	_, err := s.Serialize("s", "attributes", cyclic)
+	assert.Error(t, err, "expected an error with a cyclic tree")
+
+	_, err = s.Serialize("s", "extra", map[string]interface{}{"channel": make(chan int)})
+	assert.Error(t, err, "expected an error with an unsupported kind")
+}
@@ -0,0 +1,52 @@
+package opentsdb
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+/**
+* Has unit tests for the size bounded OpenTSDB serialization.
+* @author rnojiri
+**/
+
+// TestSerializeArrayChunked - test if the chunks have only whole lines under the limit
+func TestSerializeArrayChunked(t *testing.T) {
+
+	s := createSerializer()
+	items := createItems(10)
+
+	const maxBytes = 100
+
+	chunks, err := s.SerializeArrayChunked(maxBytes, items...)
+	if !assert.NoError(t, err, "error serializing the chunks") {
+		return
+	}
+
+	if !assert.True(t, len(chunks) > 1, "expected more than one chunk") {
+		return
+	}
+
+	var joined strings.Builder
+	expectedIndex := 0
+
+	for _, chunk := range chunks {
+
+		assert.True(t, len(chunk.Payload) <= maxBytes, "expected a chunk with at most %d bytes: %d", maxBytes, len(chunk.Payload))
+		assert.Equal(t, len(chunk.Indexes), strings.Count(chunk.Payload, "\n"), "expected one line per item")
+
+		for _, index := range chunk.Indexes {
+			assert.Equal(t, expectedIndex, index, "expected the indexes in order")
+			expectedIndex++
+		}
+
+		joined.WriteString(chunk.Payload)
+	}
+
+	assert.Equal(t, serializeArray(t, s, items), joined.String(), "expected same lines")
+
+	_, err = s.SerializeArrayChunked(10, items...)
+	assert.Error(t, err, "expected an error with an item exceeding the limit")
+}
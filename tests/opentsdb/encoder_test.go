@@ -0,0 +1,112 @@
+package opentsdb
+
+import (
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	serializer "github.com/uol/serializer/opentsdb"
+)
+
+/**
+* Has unit tests for the incremental OpenTSDB encoder.
+* @author rnojiri
+**/
+
+// recordingWriter - records each write call
+type recordingWriter struct {
+	writes []string
+}
+
+// Write - records the written data
+func (w *recordingWriter) Write(data []byte) (int, error) {
+
+	w.writes = append(w.writes, string(data))
+
+	return len(data), nil
+}
+
+// createItems - creates some items
+func createItems(size int) []*serializer.ArrayItem {
+
+	items := make([]*serializer.ArrayItem, size)
+	for i := 0; i < size; i++ {
+		items[i] = &serializer.ArrayItem{
+			Metric:    "encoder",
+			Timestamp: time.Now().Unix(),
+			Value:     float64(i),
+			Tags:      []interface{}{"host", "host" + strconv.Itoa(i)},
+		}
+	}
+
+	return items
+}
+
+// encode - encodes all items using the encoder
+func encode(t *testing.T, e *serializer.ArrayEncoder, items []*serializer.ArrayItem) *recordingWriter {
+
+	w := &recordingWriter{}
+
+	if !assert.NoError(t, e.Begin(w), "error beginning") {
+		panic("begin")
+	}
+
+	for _, item := range items {
+		if !assert.NoError(t, e.Add(item), "error adding an item") {
+			panic("add")
+		}
+	}
+
+	if !assert.NoError(t, e.End(), "error ending") {
+		panic("end")
+	}
+
+	return w
+}
+
+// TestArrayEncoder - test if the encoder output is the same of the array serialization
+func TestArrayEncoder(t *testing.T) {
+
+	s := createSerializer()
+	items := createItems(10)
+
+	expected := serializeArray(t, s, items)
+
+	w := encode(t, s.NewArrayEncoder(0, 0), items)
+	assert.Equal(t, []string{expected}, w.writes, "expected a single write without thresholds")
+
+	w = encode(t, s.NewArrayEncoder(0, 4), items)
+	assert.Len(t, w.writes, 3, "expected 2 automatic flushes and the final one")
+	assert.Equal(t, expected, strings.Join(w.writes, ""), "expected same output")
+
+	const maxBytes = 100
+
+	w = encode(t, s.NewArrayEncoder(maxBytes, 0), items)
+	assert.True(t, len(w.writes) > 1, "expected more than one write")
+	assert.Equal(t, expected, strings.Join(w.writes, ""), "expected same output")
+
+	for _, write := range w.writes {
+		assert.True(t, len(write) <= maxBytes, "expected writes with at most %d bytes: %d", maxBytes, len(write))
+		assert.True(t, strings.HasSuffix(write, "\n"), "expected only whole lines")
+	}
+}
+
+// TestArrayEncoderErrors - test the encoder errors
+func TestArrayEncoderErrors(t *testing.T) {
+
+	s := createSerializer()
+	items := createItems(1)
+	e := s.NewArrayEncoder(0, 0)
+
+	assert.Error(t, e.Add(items[0]), "expected an error adding before beginning")
+
+	w := &recordingWriter{}
+	assert.NoError(t, e.Begin(w), "expected no error beginning")
+	assert.Error(t, e.Begin(w), "expected an error beginning twice")
+	assert.Error(t, e.Add(&serializer.ArrayItem{Metric: "invalid", Tags: []interface{}{"host"}}), "expected an error with an odd number of tags")
+	assert.NoError(t, e.End(), "expected no error ending")
+	assert.Empty(t, w.writes, "expected nothing written")
+}
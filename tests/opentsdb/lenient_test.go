@@ -0,0 +1,65 @@
+package opentsdb
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	serializer "github.com/uol/serializer/opentsdb"
+	common "github.com/uol/serializer/serializer"
+)
+
+/**
+* Has unit tests for the lenient OpenTSDB serialization.
+* @author rnojiri
+**/
+
+// TestSerializeArrayLenient - test if the invalid items are skipped and reported
+func TestSerializeArrayLenient(t *testing.T) {
+
+	s := createSerializer()
+	items := createItems(5)
+
+	items[2].Tags = []interface{}{"host", nil}
+	items[4].Tags = []interface{}{"host"}
+
+	result, err := s.SerializeArrayLenient(items...)
+	if !assert.Error(t, err, "expected an error") {
+		return
+	}
+
+	batchErr, ok := err.(*common.BatchError)
+	if !assert.True(t, ok, "expected a batch error") {
+		return
+	}
+
+	if assert.Len(t, batchErr.Errors, 2, "expected two failed items") {
+		assert.Equal(t, 2, batchErr.Errors[0].Index, "expected the index of the first invalid item")
+		assert.Equal(t, 4, batchErr.Errors[1].Index, "expected the index of the second invalid item")
+	}
+
+	assert.Equal(t, serializeArray(t, s, []*serializer.ArrayItem{items[0], items[1], items[3]}), result, "expected only the valid lines")
+}
+
+// TestSerializeArrayLenientNothingSerialized - test if an empty payload is returned without items or when all items fail
+func TestSerializeArrayLenientNothingSerialized(t *testing.T) {
+
+	s := createSerializer()
+	items := createItems(2)
+
+	result, err := s.SerializeArrayLenient()
+	assert.NoError(t, err, "expected no error without items")
+	assert.Empty(t, result, "expected an empty payload without items")
+
+	items[0].Tags = []interface{}{"host", nil}
+	items[1].Tags = []interface{}{"host"}
+
+	result, err = s.SerializeArrayLenient(items...)
+	if assert.Error(t, err, "expected an error") {
+		if batchErr, ok := err.(*common.BatchError); assert.True(t, ok, "expected a batch error") {
+			assert.Len(t, batchErr.Errors, 2, "expected all items failed")
+		}
+	}
+
+	assert.Empty(t, result, "expected an empty payload when all items fail")
+}
@@ -403,3 +403,19 @@ func TestSingleTagWithNullValue(t *testing.T) {
 		return
 	}
 }
+
+// TestPoolStats - test if the buffer pool is used on serialization
+func TestPoolStats(t *testing.T) {
+
+	s := createSerializer()
+	items := createItems(10)
+
+	for _, item := range items {
+		serialize(t, s, item)
+	}
+
+	serializeArray(t, s, items)
+
+	stats := s.PoolStats()
+	assert.Equal(t, uint64(len(items)+1), stats.Hits+stats.Misses, "expected one buffer per serialization")
+}
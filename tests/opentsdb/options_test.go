@@ -0,0 +1,121 @@
+package opentsdb
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	serializer "github.com/uol/serializer/opentsdb"
+	common "github.com/uol/serializer/serializer"
+)
+
+/**
+* Has unit tests for the OpenTSDB serializer options.
+* @author rnojiri
+**/
+
+// recordingLogger - records the logged messages
+type recordingLogger struct {
+	messages []string
+}
+
+// Printf - records the message
+func (l *recordingLogger) Printf(format string, v ...interface{}) {
+
+	l.messages = append(l.messages, fmt.Sprintf(format, v...))
+}
+
+// TestNewShortcut - test if the default constructor keeps the default behaviour
+func TestNewShortcut(t *testing.T) {
+
+	s := serializer.NewWithOptions(serializer.WithBufferSize(100))
+
+	result, err := s.Serialize("metric", 10, 1.5, "b", "2", "a", 1)
+	if assert.NoError(t, err, "error serializing") {
+		assert.Equal(t, "put metric 10 1.5 b=2 a=1\n", result, "expected the default output")
+	}
+
+	_, err = s.Serialize("metric", 10, 1.5, "a", nil)
+	assert.Error(t, err, "expected an error with a null tag")
+}
+
+// TestWithSortedKeys - test if the tags are written sorted by key
+func TestWithSortedKeys(t *testing.T) {
+
+	s := serializer.NewWithOptions(serializer.WithSortedKeys())
+
+	result, err := s.Serialize("metric", 10, 1, "host", "h1", "dc", "sp", "app", "x")
+	if assert.NoError(t, err, "error serializing") {
+		assert.Equal(t, "put metric 10 1 app=x dc=sp host=h1\n", result, "expected sorted tags")
+	}
+}
+
+// TestWithFloatFormat - test if the value and float tags use the format
+func TestWithFloatFormat(t *testing.T) {
+
+	s := serializer.NewWithOptions(serializer.WithFloatFormat('f', 2))
+
+	result, err := s.Serialize("metric", 10, 1.0/3, "ratio", 0.5)
+	if assert.NoError(t, err, "error serializing") {
+		assert.Equal(t, "put metric 10 0.33 ratio=0.50\n", result, "expected formatted floats")
+	}
+}
+
+// TestWithNullPolicy - test the null tag values with each policy
+func TestWithNullPolicy(t *testing.T) {
+
+	s := serializer.NewWithOptions(serializer.WithNullPolicy(common.NullWrite))
+
+	result, err := s.Serialize("metric", 10, 1, "a", nil, "b", "2")
+	if assert.NoError(t, err, "error serializing") {
+		assert.Equal(t, "put metric 10 1 a=null b=2\n", result, "expected a null tag")
+	}
+
+	s = serializer.NewWithOptions(serializer.WithNullPolicy(common.NullSkip))
+
+	result, err = s.Serialize("metric", 10, 1, "a", "1", "b", nil)
+	if assert.NoError(t, err, "error serializing") {
+		assert.Equal(t, "put metric 10 1 a=1\n", result, "expected the null tag omitted")
+	}
+}
+
+// TestWithPanicPolicy - test the unexpected panics with each policy
+func TestWithPanicPolicy(t *testing.T) {
+
+	items := []*serializer.ArrayItem{nil}
+
+	logger := &recordingLogger{}
+	s := serializer.NewWithOptions(serializer.WithLogger(logger))
+
+	result, err := s.SerializeArray(items...)
+	assert.NoError(t, err, "expected no error by default")
+	assert.Empty(t, result, "expected an empty result")
+	assert.Len(t, logger.messages, 1, "expected the panic to be logged")
+
+	s = serializer.NewWithOptions(serializer.WithLogger(logger), serializer.WithPanicPolicy(common.PanicAsError))
+
+	_, err = s.SerializeArray(items...)
+	assert.Error(t, err, "expected the panic as an error")
+	assert.Len(t, logger.messages, 2, "expected the panic to be logged")
+
+	s = serializer.NewWithOptions(serializer.WithLogger(logger), serializer.WithPanicPolicy(common.PanicPropagate))
+
+	assert.Panics(t, func() { s.SerializeArray(items...) }, "expected the panic to be propagated")
+	assert.Len(t, logger.messages, 2, "expected no log when propagating")
+}
+
+// TestWithMaxOutputSize - test if the outputs bigger than the maximum are rejected
+func TestWithMaxOutputSize(t *testing.T) {
+
+	s := serializer.NewWithOptions(serializer.WithMaxOutputSize(30))
+
+	_, err := s.Serialize("metric", 10, 1, "host", "h1")
+	assert.NoError(t, err, "expected no error with a small output")
+
+	_, err = s.Serialize("metric", 10, 1, "host", "a very long host name")
+	assert.Error(t, err, "expected an error with a big output")
+
+	_, err = s.SerializeArray(createItems(5)...)
+	assert.Error(t, err, "expected an error with a big array")
+}
@@ -0,0 +1,49 @@
+package opentsdb
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+/**
+* Has unit tests for the parallel OpenTSDB serialization.
+* @author rnojiri
+**/
+
+// TestSerializeArrayParallel - test if the parallel output is the same of the sequential one
+func TestSerializeArrayParallel(t *testing.T) {
+
+	s := createSerializer()
+	items := createItems(101)
+
+	expected := serializeArray(t, s, items)
+
+	for _, workers := range []int{0, 1, 2, 3, 8, 200} {
+
+		result, err := s.SerializeArrayParallel(workers, items...)
+		if !assert.NoError(t, err, "error serializing the array with %d workers", workers) {
+			return
+		}
+
+		assert.Equal(t, expected, result, "expected the same output with %d workers", workers)
+	}
+}
+
+// TestSerializeArrayParallelError - test if the error of the first invalid item is returned
+func TestSerializeArrayParallelError(t *testing.T) {
+
+	s := createSerializer()
+	items := createItems(20)
+
+	items[13].Tags = []interface{}{"host"}
+	items[7].Tags = []interface{}{"host", nil}
+
+	_, expected := s.SerializeArray(items...)
+	if !assert.Error(t, expected, "expected an error") {
+		return
+	}
+
+	_, err := s.SerializeArrayParallel(4, items...)
+	assert.Equal(t, expected, err, "expected the same error of the sequential serialization")
+}
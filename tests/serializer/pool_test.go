@@ -0,0 +1,112 @@
+package serializer
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/uol/serializer/serializer"
+)
+
+/**
+* Has unit tests for the buffer pooling.
+* @author rnojiri
+**/
+
+// TestBufferPool - test the buffer capacity and the pool statistics
+func TestBufferPool(t *testing.T) {
+
+	p := serializer.NewBufferPool()
+
+	const numGets = 10
+
+	for i := 0; i < numGets; i++ {
+
+		b := p.Get(100)
+		assert.True(t, b.Cap() >= 100, "expected at least the requested capacity")
+		assert.Equal(t, 0, b.Len(), "expected an empty buffer")
+
+		b.WriteString("some data")
+		p.Put(b)
+	}
+
+	stats := p.Stats()
+	assert.Equal(t, uint64(numGets), stats.Hits+stats.Misses, "expected all gets counted")
+	assert.True(t, stats.Misses >= 1, "expected at least the first get as a miss")
+}
+
+// TestBufferPoolConcurrentStats - test if the hits never underflow while the buffers are taken concurrently
+func TestBufferPoolConcurrentStats(t *testing.T) {
+
+	p := serializer.NewBufferPool()
+
+	const numWorkers = 8
+	const numGets = 1000
+
+	var wg sync.WaitGroup
+	wg.Add(numWorkers)
+
+	for i := 0; i < numWorkers; i++ {
+
+		go func() {
+
+			defer wg.Done()
+
+			for j := 0; j < numGets; j++ {
+				p.Put(p.Get(10))
+			}
+		}()
+	}
+
+	done := make(chan struct{})
+
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+
+	for running := true; running; {
+
+		select {
+		case <-done:
+			running = false
+		default:
+		}
+
+		stats := p.Stats()
+		if !assert.True(t, stats.Hits+stats.Misses <= numWorkers*numGets, "expected no underflow of the hits: %+v", stats) {
+			return
+		}
+	}
+
+	stats := p.Stats()
+	assert.Equal(t, uint64(numWorkers*numGets), stats.Hits+stats.Misses, "expected all gets counted")
+}
+
+// TestAdaptiveSize - test if the size converges to the observed sizes
+func TestAdaptiveSize(t *testing.T) {
+
+	a := serializer.NewAdaptiveSize(10)
+	assert.Equal(t, 10, a.Get(), "expected the initial size")
+
+	for i := 0; i < 100; i++ {
+		a.Observe(200)
+	}
+
+	assert.Equal(t, 200, a.Get(), "expected the size to grow to the observed size")
+
+	for i := 0; i < 100; i++ {
+		a.Observe(50)
+	}
+
+	assert.Equal(t, 50, a.Get(), "expected the size to shrink to the observed size")
+}
+
+// TestShards - test if the shards are contiguous and cover all items
+func TestShards(t *testing.T) {
+
+	assert.Equal(t, [][2]int{{0, 4}, {4, 7}, {7, 10}}, serializer.Shards(3, 10), "expected balanced shards")
+	assert.Equal(t, [][2]int{{0, 1}, {1, 2}}, serializer.Shards(5, 2), "expected one shard per item")
+	assert.Nil(t, serializer.Shards(3, 0), "expected no shards without items")
+}